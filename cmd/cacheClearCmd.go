@@ -0,0 +1,31 @@
+package cmd
+
+import (
+	"fmt"
+	"opsi/helpers"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+var cacheClearCmd = &cobra.Command{
+	Use:   "clear",
+	Short: "Remove the local cache",
+	Long: `
+  Remove the local cache of the Gitlab projects, groups and users
+  stored in ~/.config/opsi/cache.
+	`,
+	Run: func(cmd *cobra.Command, args []string) {
+		err := helpers.ClearCache()
+		if err != nil {
+			fmt.Println(err.Error())
+			os.Exit(1)
+		}
+
+		fmt.Println("Cache cleared")
+	},
+}
+
+func init() {
+	cacheCmd.AddCommand(cacheClearCmd)
+}
@@ -0,0 +1,17 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+var cacheCmd = &cobra.Command{
+	Use:   "cache {verb}",
+	Args:  cobra.ExactArgs(1),
+	Short: "The cache commands",
+	Long:  "The cache commands",
+	Run:   func(cmd *cobra.Command, args []string) {},
+}
+
+func init() {
+	rootCmd.AddCommand(cacheCmd)
+}
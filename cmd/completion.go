@@ -0,0 +1,105 @@
+package cmd
+
+import (
+	"context"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// The completion must answer quickly,
+// the requests still running after this time are stopped.
+const completionTimeout = 5 * time.Second
+
+func completionContext(cmd *cobra.Command) (context.Context, context.CancelFunc) {
+	ctx := cmd.Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	return context.WithTimeout(ctx, completionTimeout)
+}
+
+func completeProjects(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	ctx, cancel := completionContext(cmd)
+	defer cancel()
+
+	completions, err := gitlab.CompleteProjects(ctx, toComplete)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+
+	return completions, cobra.ShellCompDirectiveNoFileComp
+}
+
+// Complete the project only as first argument
+func completeProjectArg(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return completeProjects(cmd, args, toComplete)
+}
+
+func completeGroups(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	ctx, cancel := completionContext(cmd)
+	defer cancel()
+
+	completions, err := gitlab.CompleteGroups(ctx, toComplete)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+
+	return completions, cobra.ShellCompDirectiveNoFileComp
+}
+
+// Complete the group only as first argument
+func completeGroupArg(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return completeGroups(cmd, args, toComplete)
+}
+
+// Complete the environment scopes of the project provided as first argument
+func completeEnvironmentScopes(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) == 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	ctx, cancel := completionContext(cmd)
+	defer cancel()
+
+	completions, err := gitlab.CompleteEnvironmentScopes(ctx, args[0], toComplete)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+
+	return completions, cobra.ShellCompDirectiveNoFileComp
+}
+
+// Complete the hosts not already provided
+func completeHosts(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	ctx, cancel := completionContext(cmd)
+	defer cancel()
+
+	hostNames, err := hosts.CompleteHosts(ctx, toComplete)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+
+	provided := map[string]bool{}
+	for _, arg := range args {
+		provided[arg] = true
+	}
+
+	completions := []string{}
+	for _, hostName := range hostNames {
+		if !provided[hostName] {
+			completions = append(completions, hostName)
+		}
+	}
+
+	return completions, cobra.ShellCompDirectiveNoFileComp
+}
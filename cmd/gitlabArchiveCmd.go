@@ -0,0 +1,17 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+var gitlabArchiveCmd = &cobra.Command{
+	Use:   "archive {entity}",
+	Args:  cobra.ExactArgs(1),
+	Short: "Archive an entity",
+	Long:  "Archive an entity",
+	Run:   func(cmd *cobra.Command, args []string) {},
+}
+
+func init() {
+	gitlabCmd.AddCommand(gitlabArchiveCmd)
+}
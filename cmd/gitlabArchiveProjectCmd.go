@@ -0,0 +1,48 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+var gitlabArchiveProjectCmd = &cobra.Command{
+	Use:               "project {project}",
+	Args:              cobra.MaximumNArgs(1),
+	ValidArgsFunction: completeProjectArg,
+	Short:             "Archive Gitlab projects",
+	Long: `
+  Archive a project or all the projects matching the selector.
+  The pre-checks report the open merge requests, the CI/CD variables
+  and the mirror kept on the project. The projects with open merge
+  requests are skipped unless the --force flag is provided.
+	`,
+	Example: `
+  Archive the project client-x/website
+  opsi gitlab archive project client-x/website
+
+  ---
+
+  Show what happens archiving all the projects of the group client-x
+  opsi gitlab archive project --selector group=client-x --dry-run
+
+  ---
+
+  Archive the projects of the group client-x with the topic legacy
+  opsi gitlab archive project --selector group=client-x,topic=legacy
+	`,
+	Run: func(cmd *cobra.Command, args []string) {
+		// Archive the projects
+		err := gitlab.ArchiveProjects(cmd.Context(), projectLifecycleRequest(cmd, args))
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	gitlabArchiveCmd.AddCommand(gitlabArchiveProjectCmd)
+	addProjectLifecycleFlags(gitlabArchiveProjectCmd)
+}
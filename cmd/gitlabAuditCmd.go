@@ -0,0 +1,18 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// gitlabAuditCmd represents the gitlab audit command
+var gitlabAuditCmd = &cobra.Command{
+	Use:   "audit {entity}",
+	Args:  cobra.ExactArgs(1),
+	Short: "Allow to audit a specific entity",
+	Long:  "Allow to audit a specific entity",
+	Run:   func(cmd *cobra.Command, args []string) {},
+}
+
+func init() {
+	gitlabCmd.AddCommand(gitlabAuditCmd)
+}
@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	gl "opsi/scopes/gitlab"
+
+	"github.com/spf13/cobra"
+)
+
+var gitlabAuditUsersCmd = &cobra.Command{
+	Use:   "users",
+	Short: "Report inactive and risky Gitlab users",
+	Long: `
+  Report the Gitlab users that need a review:
+
+  - inactive: no activity in the last N days (see -d flag)
+  - no-2fa: two factor authentication not enabled
+  - external-access: external users with developer access or more
+  - admin: users with administrator privileges
+  - bot-token: bot users with never expiring tokens
+
+  Using the --block flag the users reported will be blocked
+  (or deactivated with --deactivate) after confirmation.
+	`,
+	Example: `
+  Show all users to review
+  opsi gitlab audit users
+
+  ---
+
+  Show the users without activity in the last 180 days
+  opsi gitlab audit users -d 180 --reason inactive
+
+  ---
+
+  Deactivate the inactive users
+  opsi gitlab audit users --reason inactive --block --deactivate
+	`,
+	Run: func(cmd *cobra.Command, args []string) {
+		// Take flags
+		days, _ := cmd.Flags().GetInt("days")
+		reasons, _ := cmd.Flags().GetStringSlice("reason")
+		block, _ := cmd.Flags().GetBool("block")
+		deactivate, _ := cmd.Flags().GetBool("deactivate")
+		force, _ := cmd.Flags().GetBool("force")
+
+		// Audit the users
+		err := gitlab.AuditUsers(cmd.Context(), gl.AuditUsersRequest{
+			InactiveDays: days,
+			Reasons:      reasons,
+			Block:        block,
+			Deactivate:   deactivate,
+			Force:        force,
+		})
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	gitlabAuditCmd.AddCommand(gitlabAuditUsersCmd)
+	gitlabAuditUsersCmd.Flags().IntP("days", "d", 90, "The number of days without activity after which a user is inactive")
+	gitlabAuditUsersCmd.Flags().StringSliceP("reason", "r", []string{}, "Show only the users with these reasons (inactive, no-2fa, external-access, admin, bot-token)")
+	gitlabAuditUsersCmd.Flags().BoolP("block", "b", false, "Block the users reported")
+	gitlabAuditUsersCmd.Flags().Bool("deactivate", false, "Deactivate the users instead of block them. Use along with --block")
+	gitlabAuditUsersCmd.Flags().BoolP("force", "f", false, "Not ask confirmation to block")
+}
@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	gl "opsi/scopes/gitlab"
+
+	"github.com/spf13/cobra"
+)
+
+var gitlabBackupCmd = &cobra.Command{
+	Use:               "backup {project}",
+	Args:              cobra.MaximumNArgs(1),
+	ValidArgsFunction: completeProjectArg,
+	Short:             "Back up Gitlab projects with the export API",
+	Long: `
+  Export a project, the projects matching the selector, a group with
+  all its projects or all the projects of the instance.
+  The archives are downloaded in a dated directory inside the backup
+  directory of the configuration, together with a manifest.json
+  containing the size and the sha256 checksum of each archive.
+  The failed exports are retried and reported. When all the exports
+  are completed the oldest backups are removed, keeping the last ones.
+	`,
+	Example: `
+  Back up all the projects
+  opsi gitlab backup
+
+  ---
+
+  Back up the group client-x and its projects, 2 exports at a time
+  opsi gitlab backup -g client-x --parallel 2
+
+  ---
+
+  Back up the projects with the topic release keeping the last 3 backups
+  opsi gitlab backup --selector topic=release --keep 3
+	`,
+	Run: func(cmd *cobra.Command, args []string) {
+		project := ""
+		if len(args) > 0 {
+			// Take the project path or ID
+			project = args[0]
+		}
+
+		// Take flags
+		selector, _ := cmd.Flags().GetString("selector")
+		group, _ := cmd.Flags().GetString("group")
+		parallel, _ := cmd.Flags().GetInt("parallel")
+		keep, _ := cmd.Flags().GetInt("keep")
+
+		// Back up the projects
+		err := gitlab.Backup(cmd.Context(), gl.BackupRequest{
+			Project:  project,
+			Selector: selector,
+			Group:    group,
+			Parallel: parallel,
+			Keep:     keep,
+		})
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	gitlabCmd.AddCommand(gitlabBackupCmd)
+	gitlabBackupCmd.Flags().String("selector", "", "Select the projects by group, topic and search. Eg: group=client-x,topic=release")
+	gitlabBackupCmd.Flags().StringP("group", "g", "", "Back up the group and all its projects (full path or ID)")
+	gitlabBackupCmd.Flags().Int("parallel", 0, "The number of exports running at the same time (default from the configuration)")
+	gitlabBackupCmd.Flags().Int("keep", 0, "The number of backups to keep, a negative value keeps all (default from the configuration)")
+	gitlabBackupCmd.RegisterFlagCompletionFunc("group", completeGroups)
+}
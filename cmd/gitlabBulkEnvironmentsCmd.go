@@ -0,0 +1,42 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+var gitlabBulkEnvironmentsCmd = &cobra.Command{
+	Use:   "environments",
+	Short: "Align the environments of gitlab projects",
+	Long: `
+  Create the staging and production environments missing in the projects
+  and protect the production one: only the maintainers can deploy, with
+  the approvals provided by the configuration.
+	`,
+	Example: `
+  Update all projects
+  opsi gitlab bulk environments
+	`,
+	Run: func(cmd *cobra.Command, args []string) {
+		// Create the output channel for the messages
+		channel := make(chan string)
+		go func() {
+			for item := range channel {
+				fmt.Println(item)
+			}
+		}()
+
+		// Execute bulk
+		err := gitlab.BulkEnvironments(cmd.Context(), &channel)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	gitlabBulkCmd.AddCommand(gitlabBulkEnvironmentsCmd)
+}
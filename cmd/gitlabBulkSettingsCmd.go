@@ -29,7 +29,7 @@ var gitlabBulkSettingsCmd = &cobra.Command{
 		}()
 
 		// Execute bulk
-		err := gitlab.BulkSettings(&channel)
+		err := gitlab.BulkSettings(cmd.Context(), &channel)
 		if err != nil {
 			fmt.Println(err)
 			os.Exit(1)
@@ -9,8 +9,16 @@ import (
 
 // subgroupCmd represents the subgroup command
 var gitlabCreateEnvsCmd = &cobra.Command{
-	Use:   "envs {project_id} {env_file_path}",
-	Args:  cobra.ExactArgs(2),
+	Use:  "envs {project} {env_file_path}",
+	Args: cobra.ExactArgs(2),
+	ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		// The second argument is the env file
+		if len(args) == 1 {
+			return nil, cobra.ShellCompDirectiveDefault
+		}
+
+		return completeProjectArg(cmd, args, toComplete)
+	},
 	Short: "Create ENVs for Gitlab project",
 	Long: `
   Create ENVs for a specific Gitlab project.
@@ -18,8 +26,8 @@ var gitlabCreateEnvsCmd = &cobra.Command{
   flag -e. Please see the example section.
 	`,
 	Example: `	
-  Create ENVs for the project 1234.
-  opsi gitlab create envs 1234 /file/to/env.yml
+  Create ENVs for the project client-x/website.
+  opsi gitlab create envs client-x/website /file/to/env.yml
 
   ---
 
@@ -27,8 +35,8 @@ var gitlabCreateEnvsCmd = &cobra.Command{
   opsi gitlab create envs 1234 /file/to/env.yml -e staging
 	`,
 	Run: func(cmd *cobra.Command, args []string) {
-		// Take project ID
-		projectID := args[0]
+		// Take the project path or ID
+		project := args[0]
 
 		// Take env file path
 		envFile := args[1]
@@ -37,7 +45,7 @@ var gitlabCreateEnvsCmd = &cobra.Command{
 		env, _ := cmd.Flags().GetString("env")
 
 		// Create environments
-		err := gitlab.CreateEnvs(projectID, env, envFile)
+		err := gitlab.CreateEnvs(cmd.Context(), project, env, envFile)
 		if err != nil {
 			fmt.Println(err)
 			os.Exit(1)
@@ -48,4 +56,5 @@ var gitlabCreateEnvsCmd = &cobra.Command{
 func init() {
 	gitlabCreateCmd.AddCommand(gitlabCreateEnvsCmd)
 	gitlabCreateEnvsCmd.Flags().StringP("env", "e", "*", "The environment scope")
+	gitlabCreateEnvsCmd.RegisterFlagCompletionFunc("env", completeEnvironmentScopes)
 }
@@ -44,7 +44,7 @@ var gitlabCreateGroupCmd = &cobra.Command{
 		}
 
 		// Create subgroup
-		groupID, err := gitlab.CreateGroup(name, pathname, visibility)
+		groupID, err := gitlab.CreateGroup(cmd.Context(), name, pathname, visibility)
 		if err != nil {
 			fmt.Println(err)
 			os.Exit(1)
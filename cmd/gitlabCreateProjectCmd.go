@@ -17,8 +17,13 @@ var gitlabCreateProjectCmd = &cobra.Command{
 	Short: "Create a Gitlab project",
 	Long:  "This command allow to create a Gitlab project in a specific workspace",
 	Example: `
+  Create a project with name "Password manager" for subgroup client-x/tools:
+  opsi gitlab create project "Password manager" -s client-x/tools
+
+  ---
+
   Create a project with name "Password manager" for subgroup 12345:
-  opsi gitlab create project "Password manager" -s 12345 
+  opsi gitlab create project "Password manager" -s 12345
 
   ---
 
@@ -45,6 +50,21 @@ var gitlabCreateProjectCmd = &cobra.Command{
   Create a project with visibility "internal"
   opsi gitlab create project Anonymous -i internal
 
+  ---
+
+  Create a project with the content of the template project templates/laravel
+  opsi gitlab create project Bifrost -s client-x --from-template templates/laravel
+
+  ---
+
+  Create a project importing an external repository
+  opsi gitlab create project Bifrost -s client-x --import-url https://github.com/client-x/bifrost.git
+
+  ---
+
+  Create a project with the files of a local directory as first commit
+  opsi gitlab create project Bifrost -s client-x --skeleton ./skeletons/default
+
 	`,
 
 	Run: func(cmd *cobra.Command, args []string) {
@@ -52,12 +72,15 @@ var gitlabCreateProjectCmd = &cobra.Command{
 		name := args[0]
 
 		// Take flags
-		group, _ := cmd.Flags().GetInt("group")
+		group, _ := cmd.Flags().GetString("group")
 		pathname, _ := cmd.Flags().GetString("path")
 		defaultBranch, _ := cmd.Flags().GetString("branch-default")
 		mirror, _ := cmd.Flags().GetBool("mirror")
 		sharedRunners, _ := cmd.Flags().GetBool("sharedrunners")
 		visibility, _ := cmd.Flags().GetString("visibility")
+		fromTemplate, _ := cmd.Flags().GetString("from-template")
+		importURL, _ := cmd.Flags().GetString("import-url")
+		skeleton, _ := cmd.Flags().GetString("skeleton")
 
 		// Slugify the name if the pathname flag
 		// for the project is not provided
@@ -74,10 +97,13 @@ var gitlabCreateProjectCmd = &cobra.Command{
 			Mirror:        mirror,
 			SharedRunners: sharedRunners,
 			Group:         group,
+			FromTemplate:  fromTemplate,
+			ImportURL:     importURL,
+			Skeleton:      skeleton,
 		}
 
 		// Create the project
-		projectID, err := gitlab.CreateProject(payload)
+		projectID, err := gitlab.CreateProject(cmd.Context(), payload)
 
 		if err != nil {
 			fmt.Println(err)
@@ -90,13 +116,20 @@ var gitlabCreateProjectCmd = &cobra.Command{
 
 func init() {
 	gitlabCreateCmd.AddCommand(gitlabCreateProjectCmd)
-	gitlabCreateProjectCmd.Flags().IntP("group", "s", 0, "the group associated to the project. If not provided the one in the configuration will be used")
+	gitlabCreateProjectCmd.Flags().StringP("group", "s", "", "the group associated to the project (full path or ID)")
 	gitlabCreateProjectCmd.Flags().StringP("path", "p", "", "the path for the project. This flag is useful if you don't want to use the project name for the path")
 	gitlabCreateProjectCmd.Flags().StringP("branch-default", "b", "main", "the default main branch. Possible values are master or main")
 	gitlabCreateProjectCmd.Flags().BoolP("mirror", "m", false, "Enable or disable the mirroring repo. Default is false")
 	gitlabCreateProjectCmd.Flags().BoolP("sharedrunners", "r", false, "Enable or disable the shared runners. Default is true")
 	gitlabCreateProjectCmd.Flags().StringP("visibility", "i", "", "Set the visibility of the project. Allowed values are private, public, internal")
+	gitlabCreateProjectCmd.Flags().String("from-template", "", "Create the project from a template project (full path or ID)")
+	gitlabCreateProjectCmd.Flags().String("import-url", "", "Create the project importing the repository of the URL")
+	gitlabCreateProjectCmd.Flags().String("skeleton", "", "Commit the files of the directory as the first commit of the project")
+	gitlabCreateProjectCmd.MarkFlagsMutuallyExclusive("from-template", "import-url", "skeleton")
+	gitlabCreateProjectCmd.RegisterFlagCompletionFunc("from-template", completeProjects)
+	gitlabCreateProjectCmd.MarkFlagDirname("skeleton")
 
 	// Mark group as required
 	gitlabCreateProjectCmd.MarkFlagRequired("group")
+	gitlabCreateProjectCmd.RegisterFlagCompletionFunc("group", completeGroups)
 }
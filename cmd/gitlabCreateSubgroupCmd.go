@@ -14,8 +14,13 @@ var gitlabCreateSubgroupCmd = &cobra.Command{
 	Short: "Create a Gitlab subgroup",
 	Long:  "Create a Gitlab subgroup",
 	Example: `
+  Create a subgroup with name "research" attach to the group client-x
+  opsi gitlab create subgroup research -s client-x
+
+  ---
+
   Create a subgroup with name "research" attach to a specific group with id 1234
-  opsi gitlab create subgroup research -s 1234 
+  opsi gitlab create subgroup research -s 1234
 
   ---
 
@@ -26,8 +31,8 @@ var gitlabCreateSubgroupCmd = &cobra.Command{
 		// Take the name of the group
 		name := args[0]
 
-		// Take the parent from the flag
-		parent, _ := cmd.Flags().GetInt("parent")
+		// Take the parent path or ID from the flag
+		parent, _ := cmd.Flags().GetString("parent")
 
 		// Take the pathname from the flag
 		pathname, _ := cmd.Flags().GetString("path")
@@ -38,14 +43,8 @@ var gitlabCreateSubgroupCmd = &cobra.Command{
 			pathname = slugify.Slugify(name)
 		}
 
-		// Set the parent as nil if not provided
-		var parentAsPointer *int = &parent
-		if parent == 0 {
-			parentAsPointer = nil
-		}
-
 		// Create subgroup
-		subgroupID, err := gitlab.CreateSubgroup(name, pathname, parentAsPointer)
+		subgroupID, err := gitlab.CreateSubgroup(cmd.Context(), name, pathname, parent)
 		if err != nil {
 			fmt.Println(err)
 			os.Exit(1)
@@ -57,9 +56,10 @@ var gitlabCreateSubgroupCmd = &cobra.Command{
 
 func init() {
 	gitlabCreateCmd.AddCommand(gitlabCreateSubgroupCmd)
-	gitlabCreateSubgroupCmd.Flags().IntP("parent", "s", 0, "The parent of the subgroup you want create")
+	gitlabCreateSubgroupCmd.Flags().StringP("parent", "s", "", "The parent of the subgroup you want create (full path or ID)")
 	gitlabCreateSubgroupCmd.Flags().StringP("path", "p", "", "The slugify name for the subgroup")
 
 	// Mark group as required
 	gitlabCreateSubgroupCmd.MarkFlagRequired("parent")
+	gitlabCreateSubgroupCmd.RegisterFlagCompletionFunc("parent", completeGroups)
 }
@@ -9,17 +9,18 @@ import (
 )
 
 var gitlabDeleteEnvsCmd = &cobra.Command{
-	Use:   "envs {project_id}",
-	Args:  cobra.ExactArgs(1),
-	Short: "Delete ENVs for Gitlab project",
+	Use:               "envs {project}",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeProjectArg,
+	Short:             "Delete ENVs for Gitlab project",
 	Long: `
   Delete ENVs for a specific Gitlab project.
   You can also delete the env for a specific environment using the 
   flag -e. Please see the example section.
 	`,
 	Example: `	
-  Delete ENVs for the project 1234.
-  opsi gitlab delete envs 1234
+  Delete ENVs for the project client-x/website.
+  opsi gitlab delete envs client-x/website
 	
   ---
 	
@@ -32,8 +33,8 @@ var gitlabDeleteEnvsCmd = &cobra.Command{
   opsi gitlab delete envs 1234 -f
 	`,
 	Run: func(cmd *cobra.Command, args []string) {
-		// Take project ID
-		projectID := args[0]
+		// Take the project path or ID
+		project := args[0]
 
 		// Take the enviroment env if provided
 		env, _ := cmd.Flags().GetString("env")
@@ -45,7 +46,7 @@ var gitlabDeleteEnvsCmd = &cobra.Command{
 		}
 
 		// Delete environment
-		err := gitlab.DeleteEnvs(projectID, env)
+		err := gitlab.DeleteEnvs(cmd.Context(), project, env)
 		if err != nil {
 			fmt.Println(err)
 			os.Exit(1)
@@ -56,5 +57,6 @@ var gitlabDeleteEnvsCmd = &cobra.Command{
 func init() {
 	gitlabDeleteCmd.AddCommand(gitlabDeleteEnvsCmd)
 	gitlabDeleteEnvsCmd.Flags().StringP("env", "e", "*", "The environment scope")
+	gitlabDeleteEnvsCmd.RegisterFlagCompletionFunc("env", completeEnvironmentScopes)
 	gitlabDeleteEnvsCmd.Flags().BoolP("force", "f", false, "Not ask confirmation to delete")
 }
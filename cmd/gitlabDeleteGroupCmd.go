@@ -0,0 +1,57 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	gl "opsi/scopes/gitlab"
+
+	"github.com/spf13/cobra"
+)
+
+var gitlabDeleteGroupCmd = &cobra.Command{
+	Use:               "group {group}",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeGroupArg,
+	Short:             "Delete a Gitlab group",
+	Long: `
+  Delete a Gitlab group after a backup.
+  The group and each of its projects are exported and the archives
+  are downloaded in the backup directory of the configuration, then
+  the group is deleted together with the repositories of its projects
+  on the mirror targets.
+  The full path of the group must be typed to confirm.
+
+  When the delayed deletion is enabled the group is only marked for
+  deletion, the mirrors are deleted anyway: enable the mirroring again
+  after restoring it. Use --permanently-remove to delete it right away.
+	`,
+	Example: `
+  Delete the group client-x
+  opsi gitlab delete group client-x
+
+  ---
+
+  Delete the subgroup client-x/legacy without the delayed deletion
+  opsi gitlab delete group client-x/legacy --permanently-remove
+	`,
+	Run: func(cmd *cobra.Command, args []string) {
+		// Take flags
+		permanentlyRemove, _ := cmd.Flags().GetBool("permanently-remove")
+
+		// Delete the group
+		err := gitlab.DeleteGroup(cmd.Context(), gl.DeleteRequest{
+			Reference:         args[0],
+			PermanentlyRemove: permanentlyRemove,
+		})
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	gitlabDeleteCmd.AddCommand(gitlabDeleteGroupCmd)
+	gitlabDeleteGroupCmd.Flags().Bool("permanently-remove", false, "Remove the group right away, even if the delayed deletion is enabled")
+}
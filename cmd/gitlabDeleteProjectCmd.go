@@ -0,0 +1,56 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	gl "opsi/scopes/gitlab"
+
+	"github.com/spf13/cobra"
+)
+
+var gitlabDeleteProjectCmd = &cobra.Command{
+	Use:               "project {project}",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeProjectArg,
+	Short:             "Delete a Gitlab project",
+	Long: `
+  Delete a Gitlab project after a backup.
+  The project is exported and the archive is downloaded in the backup
+  directory of the configuration, then the project is deleted together
+  with its repository on the mirror target.
+  The full path of the project must be typed to confirm.
+
+  When the delayed deletion is enabled the project is only marked for
+  deletion, the mirror is deleted anyway: enable the mirroring again
+  after restoring it. Use --permanently-remove to delete it right away.
+	`,
+	Example: `
+  Delete the project client-x/website
+  opsi gitlab delete project client-x/website
+
+  ---
+
+  Delete the project client-x/website without the delayed deletion
+  opsi gitlab delete project client-x/website --permanently-remove
+	`,
+	Run: func(cmd *cobra.Command, args []string) {
+		// Take flags
+		permanentlyRemove, _ := cmd.Flags().GetBool("permanently-remove")
+
+		// Delete the project
+		err := gitlab.DeleteProject(cmd.Context(), gl.DeleteRequest{
+			Reference:         args[0],
+			PermanentlyRemove: permanentlyRemove,
+		})
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	gitlabDeleteCmd.AddCommand(gitlabDeleteProjectCmd)
+	gitlabDeleteProjectCmd.Flags().Bool("permanently-remove", false, "Remove the project right away, even if the delayed deletion is enabled")
+}
@@ -15,10 +15,15 @@ var gitlabDeprovisioningCmd = &cobra.Command{
 	Long:  "Remove an user from all groups and projects",
 	Example: `
   Remove the user john.doe from gitlab.
-  opsi gitlab deprovisioning john.doe	
+  opsi gitlab deprovisioning john.doe
+
+  ---
+
+  Remove the user with ID 1234 from gitlab.
+  opsi gitlab deprovisioning 1234
 	`,
 	Run: func(cmd *cobra.Command, args []string) {
-		// Take the username
+		// Take the username or the user ID
 		username := args[0]
 
 		// Confirm the action
@@ -28,7 +33,7 @@ var gitlabDeprovisioningCmd = &cobra.Command{
 		}
 
 		// Deprovisioning the user
-		err := gitlab.Deprovionioning(username)
+		err := gitlab.Deprovionioning(cmd.Context(), username)
 		if err != nil {
 			fmt.Println(err)
 			os.Exit(1)
@@ -0,0 +1,58 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+var gitlabListCleanUpPolicyCmd = &cobra.Command{
+	Use:               "cleanup-policy {project}",
+	Args:              cobra.MaximumNArgs(1),
+	ValidArgsFunction: completeProjectArg,
+	Short:             "List Cleanup Policies and registry usage of Gitlab projects",
+	Long: `
+  List the current Cleanup Policy of the Gitlab projects using the
+  container registry, along with the profile expected by the
+  configuration, the number of repositories, tags and the storage size.
+  The registries with the highest average size per day since the
+  creation of the project are listed at the end.
+	`,
+	Example: `
+  Show the Cleanup Policies of all the projects using the registry
+  opsi gitlab list cleanup-policy
+
+  ---
+
+  Show the Cleanup Policy of the project client-x/website
+  opsi gitlab list cleanup-policy client-x/website
+
+  ---
+
+  Show the 20 registries with the highest average size per day
+  opsi gitlab list cleanup-policy -t 20
+	`,
+	Run: func(cmd *cobra.Command, args []string) {
+		project := ""
+		if len(args) > 0 {
+			// Take the project path or ID
+			project = args[0]
+		}
+
+		// Take flags
+		top, _ := cmd.Flags().GetInt("top")
+
+		// List cleanup policies
+		err := gitlab.ListCleanUpPolicies(cmd.Context(), project, top)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	gitlabListCmd.AddCommand(gitlabListCleanUpPolicyCmd)
+	gitlabListCleanUpPolicyCmd.Flags().IntP("top", "t", 10, "The number of registries with the highest average size per day to show. Use 0 to hide them")
+}
@@ -8,13 +8,14 @@ import (
 )
 
 var gitlabListEnvsCmd = &cobra.Command{
-	Use:   "envs {project_id}",
-	Args:  cobra.ExactArgs(1),
-	Short: "List ENVs for Gitlab project",
-	Long:  "List ENVs for Gitlab project",
+	Use:               "envs {project}",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeProjectArg,
+	Short:             "List ENVs for Gitlab project",
+	Long:              "List ENVs for Gitlab project",
 	Example: `
-  Show all envs for the project 1234
-  opsi gitlab list envs 1234
+  Show all envs for the project client-x/website
+  opsi gitlab list envs client-x/website
   
   ---
 
@@ -22,14 +23,14 @@ var gitlabListEnvsCmd = &cobra.Command{
   opsi gitlab list env 1234 -e staging
 	`,
 	Run: func(cmd *cobra.Command, args []string) {
-		// Take project ID
-		projectID := args[0]
+		// Take the project path or ID
+		project := args[0]
 
 		// Take env from flag
 		env, _ := cmd.Flags().GetString("env")
 
 		// List the envs
-		err := gitlab.ListEnvs(projectID, env)
+		err := gitlab.ListEnvs(cmd.Context(), project, env)
 		if err != nil {
 			fmt.Println(err)
 			os.Exit(1)
@@ -40,4 +41,5 @@ var gitlabListEnvsCmd = &cobra.Command{
 func init() {
 	gitlabListCmd.AddCommand(gitlabListEnvsCmd)
 	gitlabListEnvsCmd.Flags().StringP("env", "e", "*", "The environment scope")
+	gitlabListEnvsCmd.RegisterFlagCompletionFunc("env", completeEnvironmentScopes)
 }
@@ -0,0 +1,66 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	gl "opsi/scopes/gitlab"
+
+	"github.com/spf13/cobra"
+)
+
+var gitlabListGroupsCmd = &cobra.Command{
+	Use:   "groups",
+	Short: "List Gitlab groups",
+	Long: `
+  List the Gitlab groups with their ID, full path, name and visibility.
+  The output can be a table, json, yaml, csv or a tree of the groups.
+	`,
+	Example: `
+  Show all groups
+  opsi gitlab list groups
+
+  ---
+
+  Show the hierarchy of all groups
+  opsi gitlab list groups -o tree
+
+  ---
+
+  Show the subgroups of client-x at any level in yaml
+  opsi gitlab list groups -p client-x -o yaml
+
+  ---
+
+  Show only the top level groups
+  opsi gitlab list groups --top-level
+	`,
+	Run: func(cmd *cobra.Command, args []string) {
+		// Take flags
+		parent, _ := cmd.Flags().GetString("parent")
+		search, _ := cmd.Flags().GetString("search")
+		topLevel, _ := cmd.Flags().GetBool("top-level")
+		output, _ := cmd.Flags().GetString("output")
+
+		// List the groups
+		err := gitlab.ListGroups(cmd.Context(), gl.ListGroupsRequest{
+			Parent:   parent,
+			Search:   search,
+			TopLevel: topLevel,
+			Output:   output,
+		})
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	gitlabListCmd.AddCommand(gitlabListGroupsCmd)
+	gitlabListGroupsCmd.Flags().StringP("parent", "p", "", "Show only the subgroups of the parent at any level (full path or ID)")
+	gitlabListGroupsCmd.Flags().StringP("search", "s", "", "Show only the groups matching the search")
+	gitlabListGroupsCmd.Flags().Bool("top-level", false, "Show only the top level groups")
+	gitlabListGroupsCmd.Flags().StringP("output", "o", "table", "The output format. Allowed values are table, json, yaml, csv, tree")
+	gitlabListGroupsCmd.RegisterFlagCompletionFunc("parent", completeGroups)
+}
@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	gl "opsi/scopes/gitlab"
+
+	"github.com/spf13/cobra"
+)
+
+var gitlabListHooksCmd = &cobra.Command{
+	Use:               "hooks {project}",
+	Args:              cobra.MaximumNArgs(1),
+	ValidArgsFunction: completeProjectArg,
+	Short:             "List the webhooks of Gitlab projects and groups",
+	Long: `
+  List the webhooks of a project, of the projects matching the selector
+  or of all the projects and groups. Only the webhooks not provided by the
+  configuration are shown, use the --all flag to show all of them.
+  The output can be a table, json, yaml or csv.
+	`,
+	Example: `
+  Show the webhooks added by hand on all projects and groups
+  opsi gitlab list hooks
+
+  ---
+
+  Show all the webhooks of the project client-x/website
+  opsi gitlab list hooks client-x/website --all
+
+  ---
+
+  Export the webhooks of the projects of the group client-x in csv
+  opsi gitlab list hooks --selector group=client-x -o csv
+	`,
+	Run: func(cmd *cobra.Command, args []string) {
+		project := ""
+		if len(args) > 0 {
+			// Take the project path or ID
+			project = args[0]
+		}
+
+		// Take flags
+		selector, _ := cmd.Flags().GetString("selector")
+		all, _ := cmd.Flags().GetBool("all")
+		output, _ := cmd.Flags().GetString("output")
+
+		// List the hooks
+		err := gitlab.ListHooks(cmd.Context(), gl.ListHooksRequest{
+			Project:  project,
+			Selector: selector,
+			All:      all,
+			Output:   output,
+		})
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	gitlabListCmd.AddCommand(gitlabListHooksCmd)
+	gitlabListHooksCmd.Flags().String("selector", "", "Select the projects by group, topic and search. Eg: group=client-x,topic=release")
+	gitlabListHooksCmd.Flags().Bool("all", false, "Show also the webhooks provided by the configuration")
+	gitlabListHooksCmd.Flags().StringP("output", "o", "table", "The output format. Allowed values are table, json, yaml, csv")
+}
@@ -0,0 +1,46 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var gitlabListMirrorsCmd = &cobra.Command{
+	Use:   "mirrors",
+	Short: "List the remote mirrors of Gitlab projects",
+	Long: `
+  List the remote mirrors of all Gitlab projects with their health status.
+  A mirror is stale when the last update is failed or the last successful
+  update is older than the threshold provided with the -t flag.
+	`,
+	Example: `
+  Show the status of all mirrors
+  opsi gitlab list mirrors
+
+  ---
+
+  Exit with an error if a mirror was not updated in the last 12 hours
+  opsi gitlab list mirrors -t 12h --fail-on-stale
+	`,
+	Run: func(cmd *cobra.Command, args []string) {
+		// Take flags
+		staleAfter, _ := cmd.Flags().GetDuration("stale-after")
+		failOnStale, _ := cmd.Flags().GetBool("fail-on-stale")
+
+		// List the mirrors
+		err := gitlab.ListMirrors(cmd.Context(), staleAfter, failOnStale)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	gitlabListCmd.AddCommand(gitlabListMirrorsCmd)
+	gitlabListMirrorsCmd.Flags().DurationP("stale-after", "t", 24*time.Hour, "The time after which a mirror without successful updates is stale")
+	gitlabListMirrorsCmd.Flags().Bool("fail-on-stale", false, "Exit with an error if any mirror is stale")
+}
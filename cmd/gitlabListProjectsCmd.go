@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	gl "opsi/scopes/gitlab"
+
+	"github.com/spf13/cobra"
+)
+
+var gitlabListProjectsCmd = &cobra.Command{
+	Use:   "projects",
+	Short: "List Gitlab projects",
+	Long: `
+  List the Gitlab projects with their ID, full path, visibility, default
+  branch, last activity, archived and shared runners status. The mirror
+  status is shown with the --with-mirror flag, or with the --mirror filter:
+  it needs a request per project.
+  The output can be a table, json, yaml, csv or a tree of the groups.
+	`,
+	Example: `
+  Show all projects
+  opsi gitlab list projects
+
+  ---
+
+  Show the projects of the group client-x and its subgroups as a tree
+  opsi gitlab list projects -g client-x -o tree
+
+  ---
+
+  Export the private projects with the mirroring enabled in csv
+  opsi gitlab list projects --visibility private --mirror -o csv
+
+  ---
+
+  Show the mirror status of the projects of the group client-x
+  opsi gitlab list projects -g client-x --with-mirror
+
+  ---
+
+  Show the archived projects matching "website" in json
+  opsi gitlab list projects -s website --archived true -o json
+	`,
+	Run: func(cmd *cobra.Command, args []string) {
+		// Take flags
+		group, _ := cmd.Flags().GetString("group")
+		search, _ := cmd.Flags().GetString("search")
+		visibility, _ := cmd.Flags().GetString("visibility")
+		topic, _ := cmd.Flags().GetString("topic")
+		archived, _ := cmd.Flags().GetString("archived")
+		mirror, _ := cmd.Flags().GetBool("mirror")
+		withMirror, _ := cmd.Flags().GetBool("with-mirror")
+		output, _ := cmd.Flags().GetString("output")
+
+		// List the projects
+		err := gitlab.ListProjects(cmd.Context(), gl.ListProjectsRequest{
+			Group:      group,
+			Search:     search,
+			Visibility: visibility,
+			Topic:      topic,
+			Archived:   archived,
+			Mirror:     mirror,
+			WithMirror: withMirror,
+			Output:     output,
+		})
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	gitlabListCmd.AddCommand(gitlabListProjectsCmd)
+	gitlabListProjectsCmd.Flags().StringP("group", "g", "", "Show only the projects of the group and its subgroups (full path or ID)")
+	gitlabListProjectsCmd.Flags().StringP("search", "s", "", "Show only the projects matching the search")
+	gitlabListProjectsCmd.Flags().String("visibility", "", "Show only the projects with the visibility provided. Allowed values are private, internal, public")
+	gitlabListProjectsCmd.Flags().String("topic", "", "Show only the projects with the topic provided")
+	gitlabListProjectsCmd.Flags().String("archived", "", "Show only the archived (true) or not archived (false) projects")
+	gitlabListProjectsCmd.Flags().Bool("mirror", false, "Show only the projects with the mirroring enabled")
+	gitlabListProjectsCmd.Flags().Bool("with-mirror", false, "Show the mirror status of the projects")
+	gitlabListProjectsCmd.Flags().StringP("output", "o", "table", "The output format. Allowed values are table, json, yaml, csv, tree")
+	gitlabListProjectsCmd.RegisterFlagCompletionFunc("group", completeGroups)
+}
@@ -0,0 +1,17 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+var gitlabMigrateCmd = &cobra.Command{
+	Use:   "migrate {entity}",
+	Args:  cobra.ExactArgs(1),
+	Short: "Migrate an entity to another Gitlab instance",
+	Long:  "Migrate an entity to another Gitlab instance",
+	Run:   func(cmd *cobra.Command, args []string) {},
+}
+
+func init() {
+	gitlabCmd.AddCommand(gitlabMigrateCmd)
+}
@@ -0,0 +1,63 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	gl "opsi/scopes/gitlab"
+
+	"github.com/spf13/cobra"
+)
+
+var gitlabMigrateProjectCmd = &cobra.Command{
+	Use:               "project {project}",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeProjectArg,
+	Short:             "Migrate a Gitlab project to another instance",
+	Long: `
+  Migrate a project to the Gitlab instance of a mirror target.
+  The project is exported and imported in the group provided, then the
+  CI/CD variables, the protected branches and tags and the cleanup policy
+  are copied and the branches and tags are verified.
+  The export is downloaded in a temporary directory, removed once the
+  import is completed.
+  The target is the default mirror (mirror) or the group path of one of
+  the mirrors. Use --repoint-mirror to keep the migrated project updated
+  through the remote mirror of the source project.
+	`,
+	Example: `
+  Migrate the project client-x/website to the group client-x of the mirror instance
+  opsi gitlab migrate project client-x/website --to mirror --group client-x
+
+  ---
+
+  Migrate the project 1234 and push the next changes to the migrated project
+  opsi gitlab migrate project 1234 --to mirror --group client-x --repoint-mirror
+	`,
+	Run: func(cmd *cobra.Command, args []string) {
+		// Take flags
+		to, _ := cmd.Flags().GetString("to")
+		group, _ := cmd.Flags().GetString("group")
+		repointMirror, _ := cmd.Flags().GetBool("repoint-mirror")
+
+		// Migrate the project
+		err := gitlab.MigrateProject(cmd.Context(), gl.MigrateRequest{
+			Project:       args[0],
+			To:            to,
+			Group:         group,
+			RepointMirror: repointMirror,
+		})
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	gitlabMigrateCmd.AddCommand(gitlabMigrateProjectCmd)
+	gitlabMigrateProjectCmd.Flags().String("to", "mirror", "The target instance: mirror or the group path of one of the mirrors")
+	gitlabMigrateProjectCmd.Flags().StringP("group", "g", "", "The target group on the target instance (full path or ID)")
+	gitlabMigrateProjectCmd.Flags().Bool("repoint-mirror", false, "Point the remote mirror of the source project to the migrated project")
+	gitlabMigrateProjectCmd.MarkFlagRequired("group")
+}
@@ -0,0 +1,17 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+var gitlabSyncCmd = &cobra.Command{
+	Use:   "sync {entity}",
+	Args:  cobra.ExactArgs(1),
+	Short: "Align an entity of the projects with a template",
+	Long:  "Align an entity of the projects with a template",
+	Run:   func(cmd *cobra.Command, args []string) {},
+}
+
+func init() {
+	gitlabCmd.AddCommand(gitlabSyncCmd)
+}
@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	gl "opsi/scopes/gitlab"
+
+	"github.com/spf13/cobra"
+)
+
+var gitlabSyncFilesCmd = &cobra.Command{
+	Use:               "files {project}",
+	Args:              cobra.MaximumNArgs(1),
+	ValidArgsFunction: completeProjectArg,
+	Short:             "Align the standard files of the projects with a template directory",
+	Long: `
+  Compare the files of a local template directory with the ones on the
+  default branch of a project or of the projects matching the selector.
+  The changes are committed on a bot branch and a merge request is opened,
+  or updated when already open. The protected branches are never touched.
+  The report shows the projects with a merge request and the ones already
+  up to date.
+	`,
+	Example: `
+  Align the project client-x/website with the files of ./templates/standard
+  opsi gitlab sync files client-x/website --template ./templates/standard
+
+  ---
+
+  Show the files to change on all the projects of the group client-x
+  opsi gitlab sync files --template ./templates/standard --selector group=client-x --dry-run
+	`,
+	Run: func(cmd *cobra.Command, args []string) {
+		project := ""
+		if len(args) > 0 {
+			// Take the project path or ID
+			project = args[0]
+		}
+
+		// Take flags
+		template, _ := cmd.Flags().GetString("template")
+		selector, _ := cmd.Flags().GetString("selector")
+		branch, _ := cmd.Flags().GetString("branch")
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+		// Sync the files
+		err := gitlab.SyncFiles(cmd.Context(), gl.SyncFilesRequest{
+			Project:  project,
+			Selector: selector,
+			Template: template,
+			Branch:   branch,
+			DryRun:   dryRun,
+		})
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	gitlabSyncCmd.AddCommand(gitlabSyncFilesCmd)
+	gitlabSyncFilesCmd.Flags().StringP("template", "t", "", "The directory with the standard files")
+	gitlabSyncFilesCmd.Flags().String("selector", "", "Select the projects by group, topic and search. Eg: group=client-x,topic=release")
+	gitlabSyncFilesCmd.Flags().StringP("branch", "b", "opsi/sync-files", "The branch of the merge request")
+	gitlabSyncFilesCmd.Flags().Bool("dry-run", false, "Show the files to change, without opening the merge requests")
+	gitlabSyncFilesCmd.MarkFlagRequired("template")
+	gitlabSyncFilesCmd.MarkFlagDirname("template")
+}
@@ -0,0 +1,17 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+var gitlabTransferCmd = &cobra.Command{
+	Use:   "transfer {entity}",
+	Args:  cobra.ExactArgs(1),
+	Short: "Transfer an entity to another namespace",
+	Long:  "Transfer an entity to another namespace",
+	Run:   func(cmd *cobra.Command, args []string) {},
+}
+
+func init() {
+	gitlabCmd.AddCommand(gitlabTransferCmd)
+}
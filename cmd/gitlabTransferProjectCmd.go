@@ -0,0 +1,51 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+var gitlabTransferProjectCmd = &cobra.Command{
+	Use:               "project {project}",
+	Args:              cobra.MaximumNArgs(1),
+	ValidArgsFunction: completeProjectArg,
+	Short:             "Transfer Gitlab projects to another group",
+	Long: `
+  Transfer a project or all the projects matching the selector
+  to the group provided with the --to-group flag.
+  The pre-checks report the open merge requests, the group variables
+  not defined in the target group and the mirrors to re-point.
+  The projects with warnings are skipped unless the --force flag is provided.
+  The remote mirrors are re-pointed when the target group uses another mirror.
+	`,
+	Example: `
+  Transfer the project client-x/website to the group client-y
+  opsi gitlab transfer project client-x/website --to-group client-y
+
+  ---
+
+  Show what happens moving all the projects of client-x to client-y
+  opsi gitlab transfer project --selector group=client-x --to-group client-y --dry-run
+	`,
+	Run: func(cmd *cobra.Command, args []string) {
+		request := projectLifecycleRequest(cmd, args)
+		request.ToGroup, _ = cmd.Flags().GetString("to-group")
+
+		// Transfer the projects
+		err := gitlab.TransferProjects(cmd.Context(), request)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	gitlabTransferCmd.AddCommand(gitlabTransferProjectCmd)
+	addProjectLifecycleFlags(gitlabTransferProjectCmd)
+	gitlabTransferProjectCmd.Flags().String("to-group", "", "The target group (full path or ID)")
+	gitlabTransferProjectCmd.MarkFlagRequired("to-group")
+	gitlabTransferProjectCmd.RegisterFlagCompletionFunc("to-group", completeGroups)
+}
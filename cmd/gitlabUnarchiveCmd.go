@@ -0,0 +1,17 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+var gitlabUnarchiveCmd = &cobra.Command{
+	Use:   "unarchive {entity}",
+	Args:  cobra.ExactArgs(1),
+	Short: "Unarchive an entity",
+	Long:  "Unarchive an entity",
+	Run:   func(cmd *cobra.Command, args []string) {},
+}
+
+func init() {
+	gitlabCmd.AddCommand(gitlabUnarchiveCmd)
+}
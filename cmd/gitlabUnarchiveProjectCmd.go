@@ -0,0 +1,41 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+var gitlabUnarchiveProjectCmd = &cobra.Command{
+	Use:               "project {project}",
+	Args:              cobra.MaximumNArgs(1),
+	ValidArgsFunction: completeProjectArg,
+	Short:             "Unarchive Gitlab projects",
+	Long: `
+  Unarchive a project or all the projects matching the selector.
+  The pre-checks report the projects without the mirroring enabled.
+	`,
+	Example: `
+  Unarchive the project client-x/website
+  opsi gitlab unarchive project client-x/website
+
+  ---
+
+  Unarchive all the projects of the group client-x
+  opsi gitlab unarchive project --selector group=client-x
+	`,
+	Run: func(cmd *cobra.Command, args []string) {
+		// Unarchive the projects
+		err := gitlab.UnarchiveProjects(cmd.Context(), projectLifecycleRequest(cmd, args))
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	gitlabUnarchiveCmd.AddCommand(gitlabUnarchiveProjectCmd)
+	addProjectLifecycleFlags(gitlabUnarchiveProjectCmd)
+}
@@ -8,24 +8,32 @@ import (
 )
 
 var gitlabUpdateCleanUpPolicyCmd = &cobra.Command{
-	Use:   "cleanup-policy {project_id}",
-	Args:  cobra.MaximumNArgs(1),
-	Short: "Update Cleanup Policy for Gitlab project",
+	Use:               "cleanup-policy {project}",
+	Args:              cobra.MaximumNArgs(1),
+	ValidArgsFunction: completeProjectArg,
+	Short:             "Update Cleanup Policy for Gitlab project",
 	Long: `
-  Update Cleanup Policy for a specific Gitlab project.`,
+  Update Cleanup Policy for a specific Gitlab project or for all projects.
+  The profile applied to each project is chosen by the rules of the
+  cleanup_policies configuration.`,
 	Example: `	
-	Update Cleanup Policy for the project 1234.
-  	opsi gitlab update cleanup-policy 1234
+  Update Cleanup Policy for the project client-x/website.
+  opsi gitlab update cleanup-policy client-x/website
+
+  ---
+
+  Update Cleanup Policy for all projects.
+  opsi gitlab update cleanup-policy
 	`,
 	Run: func(cmd *cobra.Command, args []string) {
-		projectID := ""
+		project := ""
 		if len(args) > 0 {
-			// Take project ID
-			projectID = args[0]
+			// Take the project path or ID
+			project = args[0]
 		}
 
 		// Update cleanup policy
-		err := gitlab.UpdateCleanUpPolicy(projectID)
+		err := gitlab.UpdateCleanUpPolicy(cmd.Context(), project)
 		if err != nil {
 			fmt.Println(err)
 			os.Exit(1)
@@ -14,11 +14,17 @@ import (
 var gitlabUpdateMirroringCmd = &cobra.Command{
 	Use:   "mirroring",
 	Short: "Update Gitlab Mirroring",
-	Long:  "This command updates mirroring for all GitLab repositories",
+	Long: `
+  This command updates mirroring for all GitLab repositories.
+  Each remote mirror is replaced by a new one, with the same settings and
+  the username and token of the mirror configuration: the new mirror has
+  a new ID and its sync history starts over. The token is verified
+  against the mirror instance before update any project.
+	`,
 
 	Run: func(cmd *cobra.Command, args []string) {
 		// Update mirroring
-		err := gitlab.UpdateMirroring()
+		err := gitlab.UpdateMirroring(cmd.Context())
 		if err != nil {
 			fmt.Println(err)
 			os.Exit(1)
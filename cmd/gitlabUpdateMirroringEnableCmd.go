@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+var gitlabUpdateMirroringEnableCmd = &cobra.Command{
+	Use:               "enable {project}",
+	Args:              cobra.MaximumNArgs(1),
+	ValidArgsFunction: completeProjectArg,
+	Short:             "Enable Gitlab Mirroring for existing projects",
+	Long: `
+  Enable the mirroring for a project already existing or for all the
+  projects of a group using the -g flag.
+  The project on the mirror instance is created if missing, then
+  the remote mirror is attached and the first sync is triggered.
+	`,
+	Example: `
+  Enable the mirroring for the project client-x/website
+  opsi gitlab update mirroring enable client-x/website
+
+  ---
+
+  Enable the mirroring for all the projects of the group client-x
+  opsi gitlab update mirroring enable -g client-x
+	`,
+	Run: func(cmd *cobra.Command, args []string) {
+		project := ""
+		if len(args) > 0 {
+			// Take the project path or ID
+			project = args[0]
+		}
+
+		// Take the group path or ID from the flag
+		group, _ := cmd.Flags().GetString("group")
+
+		// Enable mirroring
+		err := gitlab.EnableMirroring(cmd.Context(), project, group)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	gitlabUpdateMirroringCmd.AddCommand(gitlabUpdateMirroringEnableCmd)
+	gitlabUpdateMirroringEnableCmd.Flags().StringP("group", "g", "", "Enable the mirroring for all the projects of the group (full path or ID)")
+	gitlabUpdateMirroringEnableCmd.RegisterFlagCompletionFunc("group", completeGroups)
+}
@@ -0,0 +1,18 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// gitlabVerifyCmd represents the gitlab verify command
+var gitlabVerifyCmd = &cobra.Command{
+	Use:   "verify {entity}",
+	Args:  cobra.ExactArgs(1),
+	Short: "Allow to verify a specific entity",
+	Long:  "Allow to verify a specific entity",
+	Run:   func(cmd *cobra.Command, args []string) {},
+}
+
+func init() {
+	gitlabCmd.AddCommand(gitlabVerifyCmd)
+}
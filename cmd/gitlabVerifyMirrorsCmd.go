@@ -0,0 +1,41 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+var gitlabVerifyMirrorsCmd = &cobra.Command{
+	Use:   "mirrors",
+	Short: "Verify the consistency of the mirrored repositories",
+	Long: `
+  Compare the head commit of the protected branches and the tags of each
+  mirrored project with the ones on the mirror instance.
+
+  Each project is reported as:
+
+  - ok: the mirror holds the same code
+  - stale: the mirror is behind the primary
+  - missing: the repository or a branch is missing on the mirror
+  - divergent: the mirror holds commits unknown by the primary
+  - orphan: the mirror project is not mirrored by any project anymore
+	`,
+	Example: `
+  Verify all the mirrors
+  opsi gitlab verify mirrors
+	`,
+	Run: func(cmd *cobra.Command, args []string) {
+		// Verify the mirrors
+		err := gitlab.VerifyMirrors(cmd.Context())
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	gitlabVerifyCmd.AddCommand(gitlabVerifyMirrorsCmd)
+}
@@ -8,12 +8,22 @@ import (
 )
 
 var hostsCheckRebootCmd = &cobra.Command{
-	Use:   "check-reboot",
+	Use:   "check-reboot [host...]",
 	Short: "Check hosts need to reboot",
 	Long:  "Check hosts need to reboot. The list of hosts are the ones of hssh CLI",
+	Example: `
+  Check all the hosts
+  opsi hosts check-reboot
+
+  ---
+
+  Check only the hosts web-01 and web-02
+  opsi hosts check-reboot web-01 web-02
+	`,
+	ValidArgsFunction: completeHosts,
 	Run: func(cmd *cobra.Command, args []string) {
-		// Check reboot
-		err := hosts.CheckReboot()
+		// Check reboot of the hosts provided, or all of them
+		err := hosts.CheckReboot(cmd.Context(), args)
 		if err != nil {
 			fmt.Println(err.Error())
 			os.Exit(1)
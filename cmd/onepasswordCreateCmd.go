@@ -23,7 +23,7 @@ In specific, the following entities will be created:
   opsi 1password create "personal vault"	
 	`,
 	Run: func(cmd *cobra.Command, args []string) {
-		err := onepassword.Create(args[0])
+		err := onepassword.Create(cmd.Context(), args[0])
 		if err != nil {
 			fmt.Println(err.Error())
 			os.Exit(1)
@@ -37,7 +37,7 @@ var onepasswordDeprovisioningCmd = &cobra.Command{
 		}
 
 		// Start the deprovisioning procedure
-		err := onepassword.Deprovisioning(email)
+		err := onepassword.Deprovisioning(cmd.Context(), email)
 		if err != nil {
 			fmt.Println(err)
 			os.Exit(1)
@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"context"
 	"embed"
 	"fmt"
 	"opsi/config"
@@ -9,6 +10,9 @@ import (
 	host "opsi/scopes/hosts"
 	op "opsi/scopes/onepassword"
 	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
@@ -22,6 +26,13 @@ var hosts host.Host
 
 var ConfigTemplate embed.FS
 
+// Global deadline of the command
+var timeout time.Duration
+var cancelTimeout context.CancelFunc = func() {}
+
+// Ignore the cached data
+var noCache bool
+
 // Version of the app provided
 // in build phase
 var Version string
@@ -35,12 +46,33 @@ var rootCmd = &cobra.Command{
 	// Uncomment the following line if your bare application
 	// has an action associated with it:
 	// Run: func(cmd *cobra.Command, args []string) { },
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		if timeout > 0 {
+			ctx, cancel := context.WithTimeout(cmd.Context(), timeout)
+			cancelTimeout = cancel
+			cmd.SetContext(ctx)
+		}
+	},
 }
 
 // Execute adds all child commands to the root command and sets flags appropriately.
 // This is called by main.main(). It only needs to happen once to the rootCmd.
 func Execute() {
-	err := rootCmd.Execute()
+	// On the first signal the commands stop to start new operations
+	// and the running ones get the time to complete.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+
+	// Restore the default behaviour, so a second signal
+	// terminates the process immediately.
+	go func() {
+		<-ctx.Done()
+		stop()
+	}()
+
+	err := rootCmd.ExecuteContext(ctx)
+	cancelTimeout()
+	stop()
+
 	if err != nil {
 		os.Exit(1)
 	}
@@ -83,15 +115,26 @@ func initConfig() {
 		os.Exit(1)
 	}
 
+	helpers.SetupRequests(mainConfig.HTTP)
+	helpers.SetupCache(home+"/.config/opsi/cache", mainConfig.Cache, noCache)
+
+	onepassword = op.NewOnePassword(mainConfig.OnePassword.Address)
+
 	gitlab = git.NewGitlab(
 		mainConfig.Gitlab.ApiURL,
 		mainConfig.Gitlab.Token,
 		mainConfig.Gitlab.Mirror,
+		mainConfig.Gitlab.Mirrors,
 		mainConfig.Gitlab.Exclusions,
+		mainConfig.Gitlab.CleanupPolicies,
+		mainConfig.Gitlab.Backup,
+		mainConfig.Gitlab.Webhooks,
+		mainConfig.Gitlab.PushRules,
+		mainConfig.Gitlab.ApprovalRules,
+		mainConfig.Gitlab.Environments,
+		onepassword.Read,
 	)
 
-	onepassword = op.NewOnePassword(mainConfig.OnePassword.Address)
-
 	hosts = host.NewHosts()
 }
 
@@ -104,4 +147,7 @@ func init() {
 	}
 
 	cobra.OnInitialize(initConfig)
+
+	rootCmd.PersistentFlags().DurationVar(&timeout, "timeout", 0, "Stop the command after the duration provided, eg: 10m")
+	rootCmd.PersistentFlags().BoolVar(&noCache, "no-cache", false, "Ignore the cached projects, groups and users and refresh them")
 }
@@ -0,0 +1,37 @@
+package cmd
+
+import (
+	gl "opsi/scopes/gitlab"
+
+	"github.com/spf13/cobra"
+)
+
+// Add the flags of the commands working on a project or on a selection of projects
+func addProjectLifecycleFlags(cmd *cobra.Command) {
+	cmd.Flags().String("selector", "", "Select the projects by group, topic and search. Eg: group=client-x,topic=release")
+	cmd.Flags().Bool("dry-run", false, "Run only the pre-checks, without changing the projects")
+	cmd.Flags().Bool("force", false, "Change the projects even if the pre-checks report warnings")
+	cmd.RegisterFlagCompletionFunc("selector", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return []string{"group=", "topic=", "search="}, cobra.ShellCompDirectiveNoSpace
+	})
+}
+
+func projectLifecycleRequest(cmd *cobra.Command, args []string) gl.ProjectLifecycleRequest {
+	project := ""
+	if len(args) > 0 {
+		// Take the project path or ID
+		project = args[0]
+	}
+
+	// Take flags
+	selector, _ := cmd.Flags().GetString("selector")
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+	force, _ := cmd.Flags().GetBool("force")
+
+	return gl.ProjectLifecycleRequest{
+		Project:  project,
+		Selector: selector,
+		DryRun:   dryRun,
+		Force:    force,
+	}
+}
@@ -1,6 +1,9 @@
 package config
 
-import "opsi/scopes/gitlab"
+import (
+	"opsi/helpers"
+	"opsi/scopes/gitlab"
+)
 
 type ConfigPostamark struct {
 	Token        string `mapstructure:"token"`
@@ -9,10 +12,17 @@ type ConfigPostamark struct {
 }
 
 type ConfigGitlab struct {
-	Token      string                        `mapstructure:"token"`
-	ApiURL     string                        `mapstructure:"api_url"`
-	Exclusions gitlab.GitlabExclusionsConfig `mapstructure:"exclusions"`
-	Mirror     gitlab.GitlabMirrorOptions    `mapstructure:"mirror"`
+	Token           string                             `mapstructure:"token"`
+	ApiURL          string                             `mapstructure:"api_url"`
+	Exclusions      gitlab.GitlabExclusionsConfig      `mapstructure:"exclusions"`
+	Mirror          gitlab.GitlabMirrorOptions         `mapstructure:"mirror"`
+	Mirrors         []gitlab.GitlabMirrorOptions       `mapstructure:"mirrors"`
+	CleanupPolicies gitlab.GitlabCleanupPoliciesConfig `mapstructure:"cleanup_policies"`
+	Backup          gitlab.GitlabBackupOptions         `mapstructure:"backup"`
+	Webhooks        []gitlab.GitlabWebhookOptions      `mapstructure:"webhooks"`
+	PushRules       gitlab.GitlabPushRulesOptions      `mapstructure:"push_rules"`
+	ApprovalRules   []gitlab.GitlabApprovalRuleOptions `mapstructure:"approval_rules"`
+	Environments    gitlab.GitlabEnvironmentsOptions   `mapstructure:"environments"`
 }
 
 type ConfigOnePassword struct {
@@ -20,7 +30,9 @@ type ConfigOnePassword struct {
 }
 
 type Config struct {
-	Postmark    ConfigPostamark   `mapstructure:"postmark"`
-	Gitlab      ConfigGitlab      `mapstructure:"gitlab"`
-	OnePassword ConfigOnePassword `mapstructure:"onepassword"`
+	HTTP        helpers.RequestOptions `mapstructure:"http"`
+	Cache       helpers.CacheOptions   `mapstructure:"cache"`
+	Postmark    ConfigPostamark        `mapstructure:"postmark"`
+	Gitlab      ConfigGitlab           `mapstructure:"gitlab"`
+	OnePassword ConfigOnePassword      `mapstructure:"onepassword"`
 }
@@ -1,12 +1,15 @@
 module opsi
 
-go 1.19
+// The minimum version required by github.com/spf13/viper v1.20.1
+go 1.21.0
+
 toolchain go1.24.1
 
 require (
 	github.com/mozillazg/go-slugify v0.2.0
 	github.com/spf13/cobra v1.9.1
 	github.com/spf13/viper v1.20.1
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
@@ -25,5 +28,4 @@ require (
 	go.uber.org/multierr v1.9.0 // indirect
 	golang.org/x/sys v0.29.0 // indirect
 	golang.org/x/text v0.21.0 // indirect
-	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
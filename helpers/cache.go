@@ -0,0 +1,274 @@
+package helpers
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// The settings of the cache of the GET requests.
+type CacheOptions struct {
+	TTL      time.Duration `mapstructure:"ttl"`
+	Disabled bool          `mapstructure:"disabled"`
+}
+
+// A response stored on disk
+type cacheEntry struct {
+	URL      string      `json:"url"`
+	ETag     string      `json:"etag"`
+	Headers  http.Header `json:"headers"`
+	Body     []byte      `json:"body"`
+	StoredAt time.Time   `json:"stored_at"`
+}
+
+// A list of values stored on disk
+type cacheListEntry struct {
+	Items    []string  `json:"items"`
+	StoredAt time.Time `json:"stored_at"`
+}
+
+// The file touched when a change is made through the API.
+// The entries stored before are revalidated on the next use.
+const cacheInvalidationFile = "invalidated_at"
+
+// The headers needed to walk through the pages
+var cacheHeaders = []string{"Link", "X-Next-Page", "X-Page", "X-Per-Page", "X-Total", "X-Total-Pages"}
+
+var defaultCacheOptions = CacheOptions{
+	TTL: 10 * time.Minute,
+}
+
+var cacheOptions = defaultCacheOptions
+var cacheDir = ""
+var cacheBypass = false
+
+// Apply the settings of the cache.
+// The bypass ignores the cached entries but still refreshes them.
+func SetupCache(dir string, options CacheOptions, bypass bool) {
+	if options.TTL == 0 {
+		options.TTL = defaultCacheOptions.TTL
+	}
+
+	cacheDir = dir
+	cacheOptions = options
+	cacheBypass = bypass
+}
+
+type noCacheKey struct{}
+
+// Ignore the cached entries for the requests made with the context.
+// The entries are still refreshed. Used by the operations that must
+// see the changes made outside opsi, like the deletions and the backups.
+func WithoutCache(ctx context.Context) context.Context {
+	return context.WithValue(ctx, noCacheKey{}, true)
+}
+
+func isCacheBypassed(ctx context.Context) bool {
+	return cacheBypass || ctx.Value(noCacheKey{}) != nil
+}
+
+// Remove all the entries of the cache
+func ClearCache() error {
+	if cacheDir == "" {
+		return nil
+	}
+
+	return os.RemoveAll(cacheDir)
+}
+
+// Mark all the entries stored until now as stale.
+// Called after a change, so the next reads are revalidated.
+func InvalidateCache() {
+	if cacheDir == "" || cacheOptions.Disabled {
+		return
+	}
+
+	err := os.MkdirAll(cacheDir, 0700)
+	if err != nil {
+		return
+	}
+
+	os.WriteFile(filepath.Join(cacheDir, cacheInvalidationFile), []byte(time.Now().Format(time.RFC3339Nano)), 0600)
+}
+
+// The full URL of the request, the query params are sorted
+func cacheURL(endpoint string, queryMap map[string]string) string {
+	parsedURL, err := url.Parse(endpoint)
+	if err != nil {
+		return endpoint
+	}
+
+	query := parsedURL.Query()
+	for key, value := range queryMap {
+		query.Set(key, value)
+	}
+	parsedURL.RawQuery = query.Encode()
+
+	return parsedURL.String()
+}
+
+// The key depends on the credentials too, so the users sharing
+// the machine never see the entries of the others.
+// The credentials are hashed, never stored.
+func cacheKey(requestURL string, headers map[string]string) string {
+	keys := []string{}
+	for key := range headers {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	hash := sha256.New()
+	hash.Write([]byte(requestURL))
+	for _, key := range keys {
+		hash.Write([]byte("\n" + strings.ToLower(key) + ":" + headers[key]))
+	}
+
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+func cachePath(key string) string {
+	return filepath.Join(cacheDir, key+".json")
+}
+
+func readCacheFile(key string, entry any) bool {
+	content, err := os.ReadFile(cachePath(key))
+	if err != nil {
+		return false
+	}
+
+	return json.Unmarshal(content, entry) == nil
+}
+
+func writeCacheFile(key string, entry any) {
+	content, err := json.Marshal(entry)
+	if err != nil {
+		return
+	}
+
+	err = os.MkdirAll(cacheDir, 0700)
+	if err != nil {
+		return
+	}
+
+	// Write and rename, so a reader never sees a partial entry
+	temporaryPath := cachePath(key) + ".tmp"
+	err = os.WriteFile(temporaryPath, content, 0600)
+	if err != nil {
+		return
+	}
+
+	os.Rename(temporaryPath, cachePath(key))
+}
+
+// An entry is fresh if it is younger than the TTL
+// and no change was made after it was stored.
+func isCacheFresh(storedAt time.Time) bool {
+	if time.Since(storedAt) > cacheOptions.TTL {
+		return false
+	}
+
+	content, err := os.ReadFile(filepath.Join(cacheDir, cacheInvalidationFile))
+	if err != nil {
+		return true
+	}
+
+	invalidatedAt, err := time.Parse(time.RFC3339Nano, string(content))
+	return err != nil || storedAt.After(invalidatedAt)
+}
+
+// Same as RequestWithHeaders for a GET request, but the response is
+// taken from the cache while it is fresh. When it is stale the request
+// is sent with If-None-Match, so the server answers 304 Not Modified
+// without the body if the content is still the same.
+func CachedRequestWithHeaders(ctx context.Context, endpoint string, queryMap map[string]string, headers map[string]string) ([]byte, http.Header, error) {
+	if cacheDir == "" || cacheOptions.Disabled {
+		return RequestWithHeaders(ctx, http.MethodGet, endpoint, nil, queryMap, headers)
+	}
+
+	requestURL := cacheURL(endpoint, queryMap)
+	key := cacheKey(requestURL, headers)
+
+	var entry cacheEntry
+	found := readCacheFile(key, &entry)
+	found = found && entry.URL == requestURL && !isCacheBypassed(ctx)
+	if found && isCacheFresh(entry.StoredAt) {
+		return entry.Body, entry.Headers, nil
+	}
+
+	// Revalidate the entry
+	requestHeaders := map[string]string{}
+	for key, value := range headers {
+		requestHeaders[key] = value
+	}
+
+	if found && entry.ETag != "" {
+		requestHeaders["If-None-Match"] = entry.ETag
+	}
+
+	body, responseHeaders, err := RequestWithHeaders(ctx, http.MethodGet, endpoint, nil, queryMap, requestHeaders)
+	if found && StatusCode(err) == http.StatusNotModified {
+		entry.StoredAt = time.Now()
+		writeCacheFile(key, entry)
+
+		return entry.Body, entry.Headers, nil
+	}
+
+	if err != nil {
+		return nil, responseHeaders, err
+	}
+
+	// Keep only the headers needed to read the next pages
+	storedHeaders := http.Header{}
+	for _, header := range cacheHeaders {
+		if value := responseHeaders.Get(header); value != "" {
+			storedHeaders.Set(header, value)
+		}
+	}
+
+	writeCacheFile(key, cacheEntry{
+		URL:      requestURL,
+		ETag:     responseHeaders.Get("ETag"),
+		Headers:  storedHeaders,
+		Body:     body,
+		StoredAt: time.Now(),
+	})
+
+	return body, storedHeaders, nil
+}
+
+// Take a list of values from the cache, the list is fetched again
+// when missing or stale. Used by the shell completion, which must
+// answer quickly. The name identifies the list, it is never stored.
+func CachedList(name string, fetch func() ([]string, error)) ([]string, error) {
+	if cacheDir == "" || cacheOptions.Disabled {
+		return fetch()
+	}
+
+	hash := sha256.Sum256([]byte(name))
+	key := "list-" + hex.EncodeToString(hash[:])
+
+	var entry cacheListEntry
+	if !cacheBypass && readCacheFile(key, &entry) && isCacheFresh(entry.StoredAt) {
+		return entry.Items, nil
+	}
+
+	items, err := fetch()
+	if err != nil {
+		return nil, err
+	}
+
+	writeCacheFile(key, cacheListEntry{
+		Items:    items,
+		StoredAt: time.Now(),
+	})
+
+	return items, nil
+}
@@ -32,3 +32,20 @@ func Confirm() {
 		os.Exit(0)
 	}
 }
+
+// Ask to type the value provided to confirm a destructive action.
+// Eg: the full path of the project to delete.
+func ConfirmValue(expected string) {
+	// Show the message
+	fmt.Printf("Type %s to confirm:\n", expected)
+
+	// Read the STDIN until the user press ENTER
+	reader := bufio.NewReader(os.Stdin)
+	value, _ := reader.ReadString('\n')
+
+	// The value must match exactly
+	if strings.TrimSpace(value) != expected {
+		fmt.Println("Ok, abort procedure")
+		os.Exit(0)
+	}
+}
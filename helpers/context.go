@@ -0,0 +1,69 @@
+package helpers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Create a context for an operation already started.
+// The context is detached from the parent and it is cancelled only
+// when the grace period is elapsed since the parent is done.
+// This gives to the running requests and commands the time to complete
+// after an interruption or a timeout, instead of killing them mid-write.
+func WithGracePeriod(parent context.Context) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(context.Background())
+	grace := requestOptions.GracePeriod
+
+	go func() {
+		select {
+		case <-parent.Done():
+		case <-ctx.Done():
+			return
+		}
+
+		timer := time.NewTimer(grace)
+		defer timer.Stop()
+
+		select {
+		case <-timer.C:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	return ctx, cancel
+}
+
+// Wait for the duration provided or until the context is done.
+// Return false if the context is done.
+func Sleep(ctx context.Context, duration time.Duration) bool {
+	timer := time.NewTimer(duration)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// Print a summary of the work completed when the context is done
+// before the end of the operation, and return the error of the context.
+// Nothing is printed if the context is still alive.
+func Interrupted(ctx context.Context, completed int, total int, items string) error {
+	err := ctx.Err()
+	if err == nil {
+		return nil
+	}
+
+	reason := "Interrupted"
+	if errors.Is(err, context.DeadlineExceeded) {
+		reason = "Timeout reached"
+	}
+
+	fmt.Printf("\n%s: %d of %d %s completed\n", reason, completed, total, items)
+	return err
+}
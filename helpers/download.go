@@ -0,0 +1,106 @@
+package helpers
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+)
+
+// The downloads and the uploads can take a long time,
+// they are stopped only by the context.
+var downloadClient = &http.Client{}
+
+// Download the response of a GET request in the file provided
+// and return the size and the sha256 checksum of the file.
+// The file is written only when the download is completed,
+// so an interrupted download never leaves a partial file.
+func Download(ctx context.Context, endpoint string, headers map[string]string, destination string) (int64, string, error) {
+	for attempt := 0; ; attempt++ {
+		canRetry := attempt < requestOptions.Retries
+
+		size, checksum, err := download(ctx, endpoint, headers, destination)
+		if err == nil {
+			return size, checksum, nil
+		}
+
+		// Retry the network errors and the server errors
+		statusCode := StatusCode(err)
+		retriable := statusCode == 0 || shouldRetry(http.MethodGet, statusCode)
+		if ctx.Err() == nil && canRetry && retriable && Sleep(ctx, backoff(attempt)) {
+			continue
+		}
+
+		return 0, "", err
+	}
+}
+
+func download(ctx context.Context, endpoint string, headers map[string]string, destination string) (int64, string, error) {
+	// Don't start new downloads once the context is done
+	err := ctx.Err()
+	if err != nil {
+		return 0, "", err
+	}
+
+	err = requestLimiter.wait(ctx)
+	if err != nil {
+		return 0, "", err
+	}
+
+	requestCtx, cancel := WithGracePeriod(ctx)
+	defer cancel()
+
+	request, err := http.NewRequestWithContext(requestCtx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return 0, "", err
+	}
+
+	for key, value := range headers {
+		request.Header.Set(key, value)
+	}
+
+	response, err := downloadClient.Do(request)
+	if err != nil {
+		return 0, "", err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode < http.StatusOK || response.StatusCode > http.StatusIMUsed {
+		body, _ := io.ReadAll(response.Body)
+		return 0, "", newAPIError(request, response, body)
+	}
+
+	err = os.MkdirAll(filepath.Dir(destination), 0700)
+	if err != nil {
+		return 0, "", err
+	}
+
+	file, err := os.CreateTemp(filepath.Dir(destination), ".download-*")
+	if err != nil {
+		return 0, "", err
+	}
+	defer os.Remove(file.Name())
+
+	// Compute the checksum while writing
+	hash := sha256.New()
+	size, err := io.Copy(io.MultiWriter(file, hash), response.Body)
+	if err != nil {
+		file.Close()
+		return 0, "", err
+	}
+
+	err = file.Close()
+	if err != nil {
+		return 0, "", err
+	}
+
+	err = os.Rename(file.Name(), destination)
+	if err != nil {
+		return 0, "", err
+	}
+
+	return size, hex.EncodeToString(hash.Sum(nil)), nil
+}
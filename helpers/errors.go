@@ -0,0 +1,145 @@
+package helpers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// The error returned when the API responds with a non successful status.
+type APIError struct {
+	Method     string
+	URL        string
+	StatusCode int
+	Message    string
+	RequestID  string
+}
+
+func (e *APIError) Error() string {
+	message := fmt.Sprintf("%s %s: %d %s", e.Method, e.URL, e.StatusCode, e.Message)
+	if e.RequestID != "" {
+		message += fmt.Sprintf(" (request ID %s)", e.RequestID)
+	}
+
+	return message
+}
+
+func newAPIError(request *http.Request, response *http.Response, body []byte) *APIError {
+	return &APIError{
+		Method:     request.Method,
+		URL:        redactURL(request.URL),
+		StatusCode: response.StatusCode,
+		Message:    parseAPIMessage(body),
+		RequestID:  response.Header.Get("X-Request-Id"),
+	}
+}
+
+// Remove the credentials from the URL: the user info
+// and the query params containing tokens, passwords or secrets.
+func redactURL(requestURL *url.URL) string {
+	redacted := *requestURL
+
+	if redacted.User != nil {
+		redacted.User = url.User("REDACTED")
+	}
+
+	query := redacted.Query()
+	for key := range query {
+		lowerKey := strings.ToLower(key)
+		if strings.Contains(lowerKey, "token") || strings.Contains(lowerKey, "password") || strings.Contains(lowerKey, "secret") {
+			query.Set(key, "REDACTED")
+		}
+	}
+	redacted.RawQuery = query.Encode()
+
+	return redacted.String()
+}
+
+// Take the message of the error from the body of the response.
+// Gitlab provides the message in different formats:
+//
+//	{"message": "404 Project Not Found"}
+//	{"message": {"key": ["has already been taken"]}}
+//	{"error": "invalid_token", "error_description": "Token was revoked"}
+func parseAPIMessage(body []byte) string {
+	var payload map[string]interface{}
+	err := json.Unmarshal(body, &payload)
+	if err != nil {
+		message := strings.TrimSpace(string(body))
+		if len(message) > 500 {
+			message = message[:500] + "..."
+		}
+
+		return message
+	}
+
+	if message, ok := payload["message"]; ok {
+		return formatAPIMessage(message)
+	}
+
+	if message, ok := payload["error"]; ok {
+		if description, ok := payload["error_description"]; ok {
+			return fmt.Sprintf("%s: %s", formatAPIMessage(message), formatAPIMessage(description))
+		}
+
+		return formatAPIMessage(message)
+	}
+
+	return strings.TrimSpace(string(body))
+}
+
+func formatAPIMessage(message interface{}) string {
+	switch value := message.(type) {
+	case string:
+		return value
+	case []interface{}:
+		partials := []string{}
+		for _, item := range value {
+			partials = append(partials, formatAPIMessage(item))
+		}
+
+		return strings.Join(partials, ", ")
+	case map[string]interface{}:
+		keys := []string{}
+		for key := range value {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		partials := []string{}
+		for _, key := range keys {
+			partials = append(partials, fmt.Sprintf("%s %s", key, formatAPIMessage(value[key])))
+		}
+
+		return strings.Join(partials, "; ")
+	}
+
+	return fmt.Sprint(message)
+}
+
+// Take the status code of the error.
+// Zero is returned if the error is not an API error.
+func StatusCode(err error) int {
+	var apiError *APIError
+	if errors.As(err, &apiError) {
+		return apiError.StatusCode
+	}
+
+	return 0
+}
+
+func IsNotFound(err error) bool {
+	return StatusCode(err) == http.StatusNotFound
+}
+
+func IsConflict(err error) bool {
+	return StatusCode(err) == http.StatusConflict
+}
+
+func IsRateLimited(err error) bool {
+	return StatusCode(err) == http.StatusTooManyRequests
+}
@@ -0,0 +1,150 @@
+package helpers
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"gopkg.in/yaml.v3"
+)
+
+// The output formats supported by the list commands
+const (
+	OutputTable = "table"
+	OutputJSON  = "json"
+	OutputYAML  = "yaml"
+	OutputCSV   = "csv"
+	OutputTree  = "tree"
+)
+
+var OutputFormats = []string{OutputTable, OutputJSON, OutputYAML, OutputCSV, OutputTree}
+
+// A node of the tree view. The hierarchy is given by the path,
+// the parts of the path are separated by a slash.
+type TreeNode struct {
+	Path  string
+	Label string
+}
+
+// Check the output format provided by the user
+func ValidateOutput(format string) error {
+	for _, allowed := range OutputFormats {
+		if format == allowed {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("invalid output %s, allowed values are %s", format, strings.Join(OutputFormats, ", "))
+}
+
+// Print the data in the format requested.
+// The table and csv formats use the headers and the rows,
+// the json and yaml formats encode the data as it is.
+func PrintOutput(format string, headers []string, rows [][]string, data any) error {
+	switch format {
+	case OutputJSON:
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(data)
+	case OutputYAML:
+		encoder := yaml.NewEncoder(os.Stdout)
+		encoder.SetIndent(2)
+		err := encoder.Encode(data)
+		if err != nil {
+			return err
+		}
+
+		return encoder.Close()
+	case OutputCSV:
+		writer := csv.NewWriter(os.Stdout)
+		err := writer.Write(headers)
+		if err != nil {
+			return err
+		}
+
+		err = writer.WriteAll(rows)
+		if err != nil {
+			return err
+		}
+
+		writer.Flush()
+		return writer.Error()
+	case OutputTable:
+		writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(writer, strings.Join(headers, "\t"))
+		for _, row := range rows {
+			fmt.Fprintln(writer, strings.Join(row, "\t"))
+		}
+
+		return writer.Flush()
+	}
+
+	return ValidateOutput(format)
+}
+
+// Print the nodes as a tree, sorted by path.
+// The intermediate nodes not provided are printed with their name only.
+// Eg:
+//
+//	client-x
+//	├── backend
+//	│   └── api (#12)
+//	└── website (#34)
+func PrintTree(nodes []TreeNode) {
+	labels := map[string]string{}
+	children := map[string][]string{}
+
+	// Register the node and all its ancestors
+	var register func(path string)
+	register = func(path string) {
+		if _, ok := labels[path]; ok {
+			return
+		}
+
+		partials := strings.Split(path, "/")
+		labels[path] = partials[len(partials)-1]
+
+		parent := strings.Join(partials[:len(partials)-1], "/")
+		if parent != "" {
+			register(parent)
+		}
+
+		children[parent] = append(children[parent], path)
+	}
+
+	for _, node := range nodes {
+		path := strings.Trim(node.Path, "/")
+		register(path)
+
+		if node.Label != "" {
+			labels[path] = node.Label
+		}
+	}
+
+	var print func(path string, prefix string)
+	print = func(path string, prefix string) {
+		items := children[path]
+		sort.Strings(items)
+
+		for index, item := range items {
+			branch, indent := "├── ", "│   "
+			if index == len(items)-1 {
+				branch, indent = "└── ", "    "
+			}
+
+			// The root nodes have no branches
+			if path == "" {
+				branch, indent = "", ""
+			}
+
+			fmt.Printf("%s%s%s\n", prefix, branch, labels[item])
+			print(item, prefix+indent)
+		}
+	}
+
+	print("", "")
+}
@@ -2,65 +2,300 @@ package helpers
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
-	"errors"
 	"io"
+	"math"
+	"math/rand"
 	"net/http"
+	"strconv"
+	"sync"
+	"time"
 )
 
-func Request(method string, endpoint string, body any, queryMap map[string]string, headers map[string]string) ([]byte, error) {
-	client := http.Client{}
+// The settings of the HTTP requests.
+// These are shared by all the scopes and goroutines.
+type RequestOptions struct {
+	Timeout     time.Duration `mapstructure:"timeout"`
+	Retries     int           `mapstructure:"retries"`
+	BackoffMin  time.Duration `mapstructure:"backoff_min"`
+	BackoffMax  time.Duration `mapstructure:"backoff_max"`
+	RateLimit   float64       `mapstructure:"rate_limit"`
+	Burst       int           `mapstructure:"burst"`
+	GracePeriod time.Duration `mapstructure:"grace_period"`
+}
+
+// A token bucket limiter. Each request takes a token,
+// the tokens are refilled at the rate provided.
+type tokenBucket struct {
+	mutex  sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+var defaultRequestOptions = RequestOptions{
+	Timeout:     30 * time.Second,
+	Retries:     3,
+	BackoffMin:  500 * time.Millisecond,
+	BackoffMax:  30 * time.Second,
+	RateLimit:   10,
+	Burst:       10,
+	GracePeriod: 10 * time.Second,
+}
+
+var requestOptions = defaultRequestOptions
+var requestClient = &http.Client{Timeout: defaultRequestOptions.Timeout}
+var requestLimiter = newTokenBucket(defaultRequestOptions.RateLimit, defaultRequestOptions.Burst)
+
+// Apply the settings to all the next requests.
+// The settings not provided keep the default value.
+// A negative value of retries or rate limit disables them.
+func SetupRequests(options RequestOptions) {
+	if options.Timeout == 0 {
+		options.Timeout = defaultRequestOptions.Timeout
+	}
+
+	if options.Retries == 0 {
+		options.Retries = defaultRequestOptions.Retries
+	}
+
+	if options.BackoffMin == 0 {
+		options.BackoffMin = defaultRequestOptions.BackoffMin
+	}
+
+	if options.BackoffMax == 0 {
+		options.BackoffMax = defaultRequestOptions.BackoffMax
+	}
+
+	if options.RateLimit == 0 {
+		options.RateLimit = defaultRequestOptions.RateLimit
+	}
+
+	if options.Burst == 0 {
+		options.Burst = defaultRequestOptions.Burst
+	}
+
+	if options.GracePeriod == 0 {
+		options.GracePeriod = defaultRequestOptions.GracePeriod
+	}
+
+	requestOptions = options
+	requestClient = &http.Client{Timeout: options.Timeout}
+	requestLimiter = newTokenBucket(options.RateLimit, options.Burst)
+}
+
+func newTokenBucket(rate float64, burst int) *tokenBucket {
+	if rate <= 0 {
+		return nil
+	}
 
+	return &tokenBucket{
+		rate:   rate,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+	}
+}
+
+// Wait until a token is available or the context is done
+func (b *tokenBucket) wait(ctx context.Context) error {
+	if b == nil {
+		return nil
+	}
+
+	for {
+		b.mutex.Lock()
+
+		// Refill the bucket with the tokens accumulated since the last call
+		now := time.Now()
+		b.tokens = math.Min(b.burst, b.tokens+now.Sub(b.last).Seconds()*b.rate)
+		b.last = now
+
+		if b.tokens >= 1 {
+			b.tokens--
+			b.mutex.Unlock()
+			return nil
+		}
+
+		waitFor := time.Duration((1 - b.tokens) / b.rate * float64(time.Second))
+		b.mutex.Unlock()
+
+		if !Sleep(ctx, waitFor) {
+			return ctx.Err()
+		}
+	}
+}
+
+// The idempotent methods can be repeated without side effects
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+
+	return false
+}
+
+// A request rate limited was not processed, so it can always be retried.
+// The server errors are retried only for the idempotent methods.
+func shouldRetry(method string, statusCode int) bool {
+	if statusCode == http.StatusTooManyRequests {
+		return true
+	}
+
+	return statusCode >= http.StatusInternalServerError && isIdempotent(method)
+}
+
+// Exponential backoff with full jitter
+func backoff(attempt int) time.Duration {
+	delay := float64(requestOptions.BackoffMin) * math.Pow(2, float64(attempt))
+	delay = math.Min(delay, float64(requestOptions.BackoffMax))
+
+	return time.Duration(rand.Int63n(int64(delay) + 1))
+}
+
+// The delay asked by the server is waited in full: a retry before
+// the delay would be rejected again. The dates in the past are now.
+func serverDelay(delay time.Duration) time.Duration {
+	if delay < 0 {
+		return 0
+	}
+
+	return delay
+}
+
+// Check if the retry can start before the deadline of the context.
+// Otherwise the error is returned right away instead of waiting for nothing.
+func beforeDeadline(ctx context.Context, delay time.Duration) bool {
+	deadline, ok := ctx.Deadline()
+	return !ok || time.Until(deadline) > delay
+}
+
+// Take the delay before retry from the response headers if provided
+// by the server, otherwise use the backoff.
+func retryDelay(statusCode int, headers http.Header, attempt int) time.Duration {
+	// Retry-After can be a number of seconds or a date
+	if retryAfter := headers.Get("Retry-After"); retryAfter != "" {
+		if seconds, err := strconv.Atoi(retryAfter); err == nil {
+			return serverDelay(time.Duration(seconds) * time.Second)
+		}
+
+		if date, err := http.ParseTime(retryAfter); err == nil {
+			return serverDelay(time.Until(date))
+		}
+	}
+
+	// RateLimit-Reset is the unix time when the quota is restored.
+	// Gitlab sends it on every response, it matters only when rate limited.
+	if reset := headers.Get("RateLimit-Reset"); reset != "" && statusCode == http.StatusTooManyRequests {
+		if timestamp, err := strconv.ParseInt(reset, 10, 64); err == nil {
+			return serverDelay(time.Until(time.Unix(timestamp, 0)))
+		}
+	}
+
+	return backoff(attempt)
+}
+
+func Request(ctx context.Context, method string, endpoint string, body any, queryMap map[string]string, headers map[string]string) ([]byte, error) {
+	response, _, err := RequestWithHeaders(ctx, method, endpoint, body, queryMap, headers)
+	return response, err
+}
+
+// Same as Request but return also the headers of the response.
+// Useful to read the pagination headers.
+//
+// No request is started once the context is done. The request already
+// running gets the grace period to complete, then it is cancelled.
+func RequestWithHeaders(ctx context.Context, method string, endpoint string, body any, queryMap map[string]string, headers map[string]string) ([]byte, http.Header, error) {
 	// Convert the body in array of bytes
 	var payload []byte = nil
 	if body != nil {
 		p, err := json.Marshal(body)
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 
 		payload = p
 	}
 
-	// Add body
-	var bodyAsReader io.Reader
-	if body != nil {
-		bodyAsReader = bytes.NewReader(payload)
-	}
+	for attempt := 0; ; attempt++ {
+		canRetry := attempt < requestOptions.Retries
 
-	// Create request
-	request, err := http.NewRequest(method, endpoint, bodyAsReader)
-	if err != nil {
-		return nil, err
-	}
+		// Don't start new requests once the context is done
+		err := ctx.Err()
+		if err != nil {
+			return nil, nil, err
+		}
 
-	// Add query params
-	if queryMap != nil {
-		query := request.URL.Query()
-		for key, value := range queryMap {
-			query.Add(key, value)
+		// Wait for the limiter
+		err = requestLimiter.wait(ctx)
+		if err != nil {
+			return nil, nil, err
 		}
-		request.URL.RawQuery = query.Encode()
-	}
 
-	// Add additional headers
-	for key, value := range headers {
-		request.Header.Set(key, value)
-	}
+		// Add body. The reader must be created on each attempt.
+		var bodyAsReader io.Reader
+		if body != nil {
+			bodyAsReader = bytes.NewReader(payload)
+		}
 
-	// Execute
-	response, err := client.Do(request)
-	if err != nil {
-		return nil, err
-	}
+		// Create request
+		requestCtx, cancel := WithGracePeriod(ctx)
+		request, err := http.NewRequestWithContext(requestCtx, method, endpoint, bodyAsReader)
+		if err != nil {
+			cancel()
+			return nil, nil, err
+		}
 
-	messageAsBytes, _ := io.ReadAll(response.Body)
-	message := string(messageAsBytes)
+		// Add query params
+		if queryMap != nil {
+			query := request.URL.Query()
+			for key, value := range queryMap {
+				query.Add(key, value)
+			}
+			request.URL.RawQuery = query.Encode()
+		}
 
-	defer response.Body.Close()
-	if response.StatusCode >= http.StatusOK && response.StatusCode <= http.StatusIMUsed {
-		return messageAsBytes, nil
-	}
+		// Add additional headers
+		for key, value := range headers {
+			request.Header.Set(key, value)
+		}
+
+		// Execute
+		response, err := requestClient.Do(request)
+		if err != nil {
+			cancel()
+			if canRetry && isIdempotent(method) && Sleep(ctx, backoff(attempt)) {
+				continue
+			}
 
-	return nil, errors.New(message)
+			return nil, nil, err
+		}
+
+		messageAsBytes, err := io.ReadAll(response.Body)
+		response.Body.Close()
+		cancel()
+		if err != nil {
+			if canRetry && isIdempotent(method) && Sleep(ctx, backoff(attempt)) {
+				continue
+			}
+
+			return nil, nil, err
+		}
+
+		if response.StatusCode >= http.StatusOK && response.StatusCode <= http.StatusIMUsed {
+			return messageAsBytes, response.Header, nil
+		}
+
+		if canRetry && shouldRetry(method, response.StatusCode) {
+			delay := retryDelay(response.StatusCode, response.Header, attempt)
+			if beforeDeadline(ctx, delay) && Sleep(ctx, delay) {
+				continue
+			}
+		}
+
+		return nil, response.Header, newAPIError(request, response, messageAsBytes)
+	}
 }
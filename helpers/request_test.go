@@ -0,0 +1,120 @@
+package helpers
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestShouldRetry(t *testing.T) {
+	tests := []struct {
+		method     string
+		statusCode int
+		retry      bool
+	}{
+		{http.MethodGet, http.StatusTooManyRequests, true},
+		{http.MethodPost, http.StatusTooManyRequests, true},
+		{http.MethodGet, http.StatusBadGateway, true},
+		{http.MethodPut, http.StatusServiceUnavailable, true},
+		{http.MethodDelete, http.StatusInternalServerError, true},
+		{http.MethodPost, http.StatusBadGateway, false},
+		{http.MethodGet, http.StatusNotFound, false},
+		{http.MethodGet, http.StatusOK, false},
+	}
+
+	for _, test := range tests {
+		retry := shouldRetry(test.method, test.statusCode)
+		if retry != test.retry {
+			t.Errorf("shouldRetry(%s, %d) = %v; want %v", test.method, test.statusCode, retry, test.retry)
+		}
+	}
+}
+
+func TestRetryDelay(t *testing.T) {
+	requestOptions = defaultRequestOptions
+	unix := func(delay time.Duration) string {
+		return strconv.FormatInt(time.Now().Add(delay).Unix(), 10)
+	}
+
+	tests := []struct {
+		name       string
+		statusCode int
+		headers    map[string]string
+		min        time.Duration
+		max        time.Duration
+	}{
+		{
+			name:       "retry after seconds",
+			statusCode: http.StatusTooManyRequests,
+			headers:    map[string]string{"Retry-After": "10"},
+			min:        10 * time.Second,
+			max:        10 * time.Second,
+		},
+		{
+			name:       "retry after longer than the backoff",
+			statusCode: http.StatusServiceUnavailable,
+			headers:    map[string]string{"Retry-After": "3600"},
+			min:        time.Hour,
+			max:        time.Hour,
+		},
+		{
+			name:       "retry after date in the past",
+			statusCode: http.StatusTooManyRequests,
+			headers:    map[string]string{"Retry-After": time.Now().Add(-time.Hour).UTC().Format(http.TimeFormat)},
+			min:        0,
+			max:        0,
+		},
+		{
+			name:       "rate limit reset when rate limited",
+			statusCode: http.StatusTooManyRequests,
+			headers:    map[string]string{"RateLimit-Reset": unix(20 * time.Second)},
+			min:        18 * time.Second,
+			max:        20 * time.Second,
+		},
+		{
+			name:       "rate limit reset longer than the backoff",
+			statusCode: http.StatusTooManyRequests,
+			headers:    map[string]string{"RateLimit-Reset": unix(time.Hour)},
+			min:        time.Hour - 2*time.Second,
+			max:        time.Hour,
+		},
+		{
+			name:       "rate limit reset ignored on server errors",
+			statusCode: http.StatusBadGateway,
+			headers:    map[string]string{"RateLimit-Reset": unix(20 * time.Second)},
+			min:        0,
+			max:        requestOptions.BackoffMin,
+		},
+	}
+
+	for _, test := range tests {
+		headers := http.Header{}
+		for key, value := range test.headers {
+			headers.Set(key, value)
+		}
+
+		delay := retryDelay(test.statusCode, headers, 0)
+		if delay < test.min || delay > test.max {
+			t.Errorf("%s: retryDelay = %s; want between %s and %s", test.name, delay, test.min, test.max)
+		}
+	}
+}
+
+func TestBeforeDeadline(t *testing.T) {
+	if !beforeDeadline(context.Background(), time.Hour) {
+		t.Error("beforeDeadline without deadline = false; want true")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	if !beforeDeadline(ctx, 10*time.Second) {
+		t.Error("beforeDeadline of a delay before the deadline = false; want true")
+	}
+
+	if beforeDeadline(ctx, time.Hour) {
+		t.Error("beforeDeadline of a delay after the deadline = true; want false")
+	}
+}
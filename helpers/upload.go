@@ -0,0 +1,86 @@
+package helpers
+
+import (
+	"context"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"path/filepath"
+)
+
+// Send a file with a multipart POST request along with the fields provided.
+// The file is streamed, so big archives are never loaded in memory.
+// The upload is not retried: the endpoints receiving files are not idempotent.
+func Upload(ctx context.Context, endpoint string, headers map[string]string, fields map[string]string, fileField string, filePath string) ([]byte, error) {
+	// Don't start new uploads once the context is done
+	err := ctx.Err()
+	if err != nil {
+		return nil, err
+	}
+
+	err = requestLimiter.wait(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	// Write the body while it is sent
+	reader, writer := io.Pipe()
+	form := multipart.NewWriter(writer)
+	go func() {
+		for key, value := range fields {
+			err := form.WriteField(key, value)
+			if err != nil {
+				writer.CloseWithError(err)
+				return
+			}
+		}
+
+		part, err := form.CreateFormFile(fileField, filepath.Base(filePath))
+		if err == nil {
+			_, err = io.Copy(part, file)
+		}
+		if err == nil {
+			err = form.Close()
+		}
+
+		writer.CloseWithError(err)
+	}()
+
+	requestCtx, cancel := WithGracePeriod(ctx)
+	defer cancel()
+
+	request, err := http.NewRequestWithContext(requestCtx, http.MethodPost, endpoint, reader)
+	if err != nil {
+		reader.Close()
+		return nil, err
+	}
+
+	for key, value := range headers {
+		request.Header.Set(key, value)
+	}
+	request.Header.Set("Content-Type", form.FormDataContentType())
+
+	response, err := downloadClient.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	body, err := io.ReadAll(response.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if response.StatusCode < http.StatusOK || response.StatusCode > http.StatusIMUsed {
+		return nil, newAPIError(request, response, body)
+	}
+
+	return body, nil
+}
@@ -2,12 +2,13 @@ package helpers
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"os/exec"
 )
 
 func Which(command string) string {
-	response, err := Exec("which", command)
+	response, err := Exec(context.Background(), "which", command)
 	if err != nil {
 		return ""
 	}
@@ -15,9 +16,18 @@ func Which(command string) string {
 	return string(response)
 }
 
-func Exec(command string, args ...string) ([]byte, error) {
+// Execute a command. No command is started once the context is done,
+// the command already running gets the grace period to complete.
+func Exec(ctx context.Context, command string, args ...string) ([]byte, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	execCtx, cancel := WithGracePeriod(ctx)
+	defer cancel()
+
 	var stderr, stdout bytes.Buffer
-	cmd := exec.Command(command, args...)
+	cmd := exec.CommandContext(execCtx, command, args...)
 
 	cmd.Stderr = &stderr
 	cmd.Stdout = &stdout
@@ -0,0 +1,305 @@
+package gitlab
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"opsi/helpers"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"text/tabwriter"
+	"time"
+)
+
+// The settings of the backups not provided take the default value.
+// The negative values disable the retries and the rotation.
+func (g *gitlab) backupSettings(options BackupRequest) (int, int, int) {
+	parallel := options.Parallel
+	if parallel <= 0 {
+		parallel = g.backup.Parallel
+	}
+	if parallel <= 0 {
+		parallel = gitlabDefaultBackupParallel
+	}
+
+	keep := options.Keep
+	if keep == 0 {
+		keep = g.backup.Keep
+	}
+	if keep == 0 {
+		keep = gitlabDefaultBackupKeep
+	}
+
+	retries := g.backup.Retries
+	if retries == 0 {
+		retries = gitlabDefaultBackupRetries
+	}
+	if retries < 0 {
+		retries = 0
+	}
+
+	return parallel, keep, retries
+}
+
+// Take the projects to back up. Without a project, a selector
+// or a group, all the projects of the instance are taken.
+func (g *gitlab) backupEntries(ctx context.Context, options BackupRequest) ([]gitlabBackupEntry, error) {
+	if options.Group != "" && (options.Project != "" || options.Selector != "") {
+		return nil, fmt.Errorf("provide a group, a project or a selector, not more")
+	}
+
+	var projects []gitlabProjectResponse
+	entries := []gitlabBackupEntry{}
+
+	var err error
+	switch {
+	case options.Group != "":
+		group, err := g.resolveGroup(ctx, options.Group)
+		if err != nil {
+			return nil, err
+		}
+
+		// The group export contains the subgroups but not the projects
+		entries = append(entries, gitlabBackupEntry{
+			Kind:     gitlabBackupKindGroup,
+			ID:       group.ID,
+			FullPath: group.FullPath,
+		})
+
+		projects, err = g.listGroupProjects(ctx, group.ID)
+		if err != nil {
+			return nil, err
+		}
+	case options.Project != "" || options.Selector != "":
+		projects, err = g.selectProjects(ctx, options.Project, options.Selector)
+	default:
+		projects, err = g.allProjects(ctx)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	for _, project := range projects {
+		entries = append(entries, gitlabBackupEntry{
+			Kind:     gitlabBackupKindProject,
+			ID:       project.ID,
+			FullPath: project.PathWithNamespace,
+		})
+	}
+
+	return entries, nil
+}
+
+// Export the entry, retrying the failures
+func (g *gitlab) backupEntry(ctx context.Context, entry gitlabBackupEntry, directory string, retries int) gitlabBackupEntry {
+	for attempt := 0; attempt <= retries; attempt++ {
+		if attempt > 0 {
+			fmt.Printf("Retrying the export of %s (%d/%d)...\n", entry.FullPath, attempt, retries)
+			if !helpers.Sleep(ctx, gitlabExportPollInterval) {
+				break
+			}
+		}
+
+		entry.Attempts++
+
+		var file gitlabBackupFile
+		var err error
+		if entry.Kind == gitlabBackupKindGroup {
+			file, err = g.exportGroup(ctx, gitlabSubgroupResponse{ID: entry.ID, FullPath: entry.FullPath}, directory)
+		} else {
+			file, err = g.exportProject(ctx, gitlabProjectResponse{ID: entry.ID, PathWithNamespace: entry.FullPath}, directory)
+		}
+
+		if err == nil {
+			entry.Status = gitlabBackupOK
+			entry.File = filepath.Base(file.Path)
+			entry.Size = file.Size
+			entry.SHA256 = file.SHA256
+			entry.Error = ""
+			return entry
+		}
+
+		entry.Status = gitlabBackupFailed
+		entry.Error = err.Error()
+
+		// Interrupted, don't retry
+		if ctx.Err() != nil {
+			break
+		}
+	}
+
+	return entry
+}
+
+func writeBackupManifest(directory string, manifest gitlabBackupManifest) error {
+	content, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(filepath.Join(directory, gitlabBackupManifestName), content, 0600)
+}
+
+// Remove the oldest backups keeping the last generations.
+// Only the directories of the backups with a manifest are considered.
+func rotateBackups(baseDirectory string, keep int) ([]string, error) {
+	files, err := os.ReadDir(baseDirectory)
+	if err != nil {
+		return nil, err
+	}
+
+	generations := []string{}
+	for _, file := range files {
+		if !file.IsDir() {
+			continue
+		}
+
+		_, err := time.Parse(gitlabBackupDirectoryFormat, file.Name())
+		if err != nil {
+			continue
+		}
+
+		_, err = os.Stat(filepath.Join(baseDirectory, file.Name(), gitlabBackupManifestName))
+		if err != nil {
+			continue
+		}
+
+		generations = append(generations, file.Name())
+	}
+
+	// The newest first
+	sort.Sort(sort.Reverse(sort.StringSlice(generations)))
+
+	removed := []string{}
+	for index, generation := range generations {
+		if index < keep {
+			continue
+		}
+
+		err := os.RemoveAll(filepath.Join(baseDirectory, generation))
+		if err != nil {
+			return removed, err
+		}
+
+		removed = append(removed, generation)
+	}
+
+	return removed, nil
+}
+
+// Print the results of the backup
+// and return the number of failures.
+func printBackupResults(entries []gitlabBackupEntry) int {
+	failed := 0
+	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(writer, "KIND\tPATH\tRESULT\tATTEMPTS\tSIZE\tMESSAGE")
+	for _, entry := range entries {
+		size := ""
+		if entry.Status == gitlabBackupOK {
+			size = formatBytes(entry.Size)
+		} else {
+			failed++
+		}
+
+		fmt.Fprintf(writer, "%s\t%s\t%s\t%d\t%s\t%s\n", entry.Kind, entry.FullPath, entry.Status, entry.Attempts, size, entry.Error)
+	}
+	writer.Flush()
+
+	return failed
+}
+
+func (g *gitlab) Backup(ctx context.Context, options BackupRequest) error {
+	parallel, keep, retries := g.backupSettings(options)
+
+	baseDirectory, err := g.backupDirectory()
+	if err != nil {
+		return err
+	}
+
+	// The projects created since the last refresh of the cache must be exported too
+	entries, err := g.backupEntries(helpers.WithoutCache(ctx), options)
+	if err != nil {
+		return err
+	}
+
+	manifest := gitlabBackupManifest{
+		Instance:  g.apiURL,
+		StartedAt: time.Now().UTC(),
+	}
+
+	// Each backup has its own dated directory
+	directory := filepath.Join(baseDirectory, manifest.StartedAt.Format(gitlabBackupDirectoryFormat))
+	err = os.MkdirAll(directory, 0700)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Backup of %d entries in %s\n", len(entries), directory)
+
+	// Run the exports in parallel, at most parallel at a time
+	wg := sync.WaitGroup{}
+	slots := make(chan struct{}, parallel)
+	results := make([]gitlabBackupEntry, len(entries))
+	for index, entry := range entries {
+		slots <- struct{}{}
+
+		// Don't start other exports once interrupted
+		if ctx.Err() != nil {
+			<-slots
+			break
+		}
+
+		wg.Add(1)
+		go func(index int, entry gitlabBackupEntry) {
+			defer wg.Done()
+			defer func() { <-slots }()
+
+			results[index] = g.backupEntry(ctx, entry, directory, retries)
+		}(index, entry)
+	}
+
+	wg.Wait()
+
+	// The manifest contains only the entries started
+	for _, result := range results {
+		if result.Status != "" {
+			manifest.Entries = append(manifest.Entries, result)
+		}
+	}
+	manifest.CompletedAt = time.Now().UTC()
+
+	err = writeBackupManifest(directory, manifest)
+	if err != nil {
+		return err
+	}
+
+	// Print the report
+	fmt.Println()
+	failed := printBackupResults(manifest.Entries)
+
+	err = helpers.Interrupted(ctx, len(manifest.Entries)-failed, len(entries), "exports")
+	if err != nil {
+		return err
+	}
+
+	// Keep the old backups until a complete one is available
+	if failed > 0 {
+		return fmt.Errorf("%d exports failed, the previous backups are kept", failed)
+	}
+
+	if keep > 0 {
+		removed, err := rotateBackups(baseDirectory, keep)
+		if err != nil {
+			return err
+		}
+
+		for _, generation := range removed {
+			fmt.Printf("Removed the old backup %s\n", generation)
+		}
+	}
+
+	return nil
+}
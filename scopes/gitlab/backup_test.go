@@ -0,0 +1,72 @@
+package gitlab
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestRotateBackups(t *testing.T) {
+	// The backups with a manifest, the newest last
+	generations := []string{"20240101-020000", "20240102-020000", "20240103-020000", "20240104-020000"}
+
+	tests := []struct {
+		keep    int
+		removed []string
+	}{
+		{2, []string{"20240102-020000", "20240101-020000"}},
+		{4, []string{}},
+		{10, []string{}},
+	}
+
+	for _, test := range tests {
+		directory := t.TempDir()
+		for _, generation := range generations {
+			err := os.MkdirAll(filepath.Join(directory, generation), 0700)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = os.WriteFile(filepath.Join(directory, generation, gitlabBackupManifestName), []byte("{}"), 0600)
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		// Never touched: an incomplete backup, a directory and a file not made by the backups
+		untouched := []string{"20231231-020000", "manual", "20230101-020000.tar.gz"}
+		for _, name := range untouched[:2] {
+			err := os.MkdirAll(filepath.Join(directory, name), 0700)
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		err := os.WriteFile(filepath.Join(directory, untouched[2]), []byte{}, 0600)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		removed, err := rotateBackups(directory, test.keep)
+		if err != nil {
+			t.Fatalf("rotateBackups(%d) failed: %s", test.keep, err)
+		}
+
+		if !reflect.DeepEqual(removed, test.removed) {
+			t.Errorf("rotateBackups(%d) = %v; want %v", test.keep, removed, test.removed)
+		}
+
+		for _, name := range removed {
+			if _, err := os.Stat(filepath.Join(directory, name)); !os.IsNotExist(err) {
+				t.Errorf("rotateBackups(%d) didn't remove %s", test.keep, name)
+			}
+		}
+
+		for _, name := range untouched {
+			if _, err := os.Stat(filepath.Join(directory, name)); err != nil {
+				t.Errorf("rotateBackups(%d) removed %s", test.keep, name)
+			}
+		}
+	}
+}
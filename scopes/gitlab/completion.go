@@ -0,0 +1,128 @@
+package gitlab
+
+import (
+	"context"
+	"fmt"
+	"opsi/helpers"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Check if the value matches what the user is typing.
+// The match is done on the whole path and on each part of it,
+// so "web" completes "client-x/website" too.
+func matchesCompletion(value string, toComplete string) bool {
+	value = strings.ToLower(value)
+	toComplete = strings.ToLower(toComplete)
+
+	if strings.HasPrefix(value, toComplete) {
+		return true
+	}
+
+	for _, partial := range strings.Split(value, "/") {
+		if strings.HasPrefix(partial, toComplete) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Complete the project paths, or the project IDs if the user
+// is typing a number. Each value is followed by its description.
+func (g *gitlab) CompleteProjects(ctx context.Context, toComplete string) ([]string, error) {
+	projects, err := g.allProjects(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	completions := []string{}
+	for _, project := range projects {
+		id := strconv.Itoa(project.ID)
+
+		if isNumericID(toComplete) {
+			if strings.HasPrefix(id, toComplete) {
+				completions = append(completions, fmt.Sprintf("%s\t%s", id, project.PathWithNamespace))
+			}
+			continue
+		}
+
+		if matchesCompletion(project.PathWithNamespace, toComplete) {
+			completions = append(completions, fmt.Sprintf("%s\t#%s", project.PathWithNamespace, id))
+		}
+	}
+
+	sort.Strings(completions)
+	return completions, nil
+}
+
+// Complete the group full paths, or the group IDs
+// if the user is typing a number.
+func (g *gitlab) CompleteGroups(ctx context.Context, toComplete string) ([]string, error) {
+	groups, err := collect[gitlabSubgroupResponse](ctx, g.cachedRequestWithHeaders, "/groups", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	completions := []string{}
+	for _, group := range groups {
+		id := strconv.Itoa(group.ID)
+
+		if isNumericID(toComplete) {
+			if strings.HasPrefix(id, toComplete) {
+				completions = append(completions, fmt.Sprintf("%s\t%s", id, group.FullPath))
+			}
+			continue
+		}
+
+		if matchesCompletion(group.FullPath, toComplete) {
+			completions = append(completions, fmt.Sprintf("%s\t#%s", group.FullPath, id))
+		}
+	}
+
+	sort.Strings(completions)
+	return completions, nil
+}
+
+// Complete the environment scopes used by the variables of the project.
+// Only the scopes are cached, never the values of the variables.
+func (g *gitlab) CompleteEnvironmentScopes(ctx context.Context, projectRef string, toComplete string) ([]string, error) {
+	project, err := g.resolveProject(ctx, projectRef)
+	if err != nil {
+		return nil, err
+	}
+
+	name := fmt.Sprintf("environment-scopes:%s:%s:%d", g.apiURL, g.token, project.ID)
+	scopes, err := helpers.CachedList(name, func() ([]string, error) {
+		variables, err := g.listVariables(ctx, strconv.Itoa(project.ID), "all")
+		if err != nil {
+			return nil, err
+		}
+
+		unique := map[string]bool{"*": true}
+		for _, variable := range variables {
+			unique[variable.EnvironmentScope] = true
+		}
+
+		scopes := []string{}
+		for scope := range unique {
+			scopes = append(scopes, scope)
+		}
+		sort.Strings(scopes)
+
+		return scopes, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	completions := []string{}
+	for _, scope := range scopes {
+		if strings.HasPrefix(scope, toComplete) {
+			completions = append(completions, scope)
+		}
+	}
+
+	return completions, nil
+}
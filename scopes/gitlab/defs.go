@@ -1,5 +1,14 @@
 package gitlab
 
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+)
+
 const gitlabOwnerPermission int = 50
 const gitlabMaintainerPermission int = 40
 const gitlabDeveloperPermission int = 30
@@ -7,52 +16,375 @@ const gitlabDefaultGroupMemberMaintainer string = "default_group_member_maintain
 const gitlabDefaultGroupMemberDeveloper string = "default_group_member_developer"
 const gitlabDefaultGroupMemberOwner string = "default_group_member_owner"
 const gitlabDefaultGroupMember string = "default_group_member"
+const gitlabAuditReasonInactive string = "inactive"
+const gitlabAuditReasonNo2FA string = "no-2fa"
+const gitlabAuditReasonExternal string = "external-access"
+const gitlabAuditReasonAdmin string = "admin"
+const gitlabAuditReasonBotToken string = "bot-token"
+const gitlabMirrorUpdateRecreated string = "recreated"
+const gitlabMirrorUpdateEnabled string = "enabled"
+const gitlabMirrorUpdateSkipped string = "skipped"
+const gitlabMirrorUpdateFailed string = "failed"
+const gitlabMirrorVerifyOK string = "ok"
+const gitlabMirrorVerifyStale string = "stale"
+const gitlabMirrorVerifyMissing string = "missing"
+const gitlabMirrorVerifyDivergent string = "divergent"
+const gitlabMirrorVerifyOrphan string = "orphan"
+const gitlabMirrorVerifyError string = "error"
+const gitlabMirrorProviderGitlab string = "gitlab"
+const gitlabMirrorProviderGithub string = "github"
+const gitlabMirrorProviderGit string = "git"
+const githubDefaultApiURL string = "https://api.github.com"
+const gitlabCleanupProfileStandard string = "standard"
+const gitlabCleanupProfileNone string = "none"
+const gitlabExportFinished string = "finished"
+const gitlabExportFailed string = "failed"
+const gitlabExportPollInterval = 5 * time.Second
+const gitlabDefaultExportTimeout = 30 * time.Minute
+const gitlabDefaultBackupParallel = 4
+const gitlabDefaultBackupKeep = 7
+const gitlabDefaultBackupRetries = 2
+const gitlabBackupDirectoryFormat = "20060102-150405"
+const gitlabBackupManifestName = "manifest.json"
+const gitlabBackupKindProject string = "project"
+const gitlabBackupKindGroup string = "group"
+const gitlabBackupOK string = "ok"
+const gitlabMigrationTargetMirror string = "mirror"
+const gitlabImportFinished string = "finished"
+const gitlabImportFailed string = "failed"
+const gitlabBackupFailed string = "failed"
+const gitlabLifecycleArchived string = "archived"
+const gitlabLifecycleUnarchived string = "unarchived"
+const gitlabLifecycleTransferred string = "transferred"
+const gitlabLifecyclePlanned string = "planned"
+const gitlabLifecycleSkipped string = "skipped"
+const gitlabLifecycleFailed string = "failed"
+const gitlabSyncMergeRequestOpened string = "opened"
+const gitlabSyncMergeRequestUpdated string = "updated"
+const gitlabSyncUpToDate string = "up-to-date"
+const gitlabSyncDefaultBranch string = "opsi/sync-files"
+const gitlabWebhookLevelProject string = "project"
+const gitlabWebhookLevelGroup string = "group"
+const gitlabWebhookMarker string = "opsi:"
+const gitlabApprovalRuleRegular string = "regular"
+const gitlabEnvironmentStaging string = "staging"
+const gitlabEnvironmentProduction string = "production"
+
+// The push rules and the approval rules need a paid tier
+var errTierUnavailable = errors.New("not available on the tier of the Gitlab instance")
 
 type gitlab struct {
-	token      string
-	apiURL     string
-	mirror     GitlabMirrorOptions
-	exclusions GitlabExclusionsConfig
+	token           string
+	apiURL          string
+	mirror          GitlabMirrorOptions
+	mirrors         []GitlabMirrorOptions
+	exclusions      GitlabExclusionsConfig
+	cleanupPolicies GitlabCleanupPoliciesConfig
+	backup          GitlabBackupOptions
+	webhooks        []GitlabWebhookOptions
+	pushRules       GitlabPushRulesOptions
+	approvalRules   []GitlabApprovalRuleOptions
+	environments    GitlabEnvironmentsOptions
+	secrets         SecretResolver
+	secretsCache    map[string]string
+	secretsMutex    sync.Mutex
 }
 
+// Read a secret by its reference, eg: from 1Password.
+// The gitlab scope never stores the secrets.
+type SecretResolver func(context.Context, string) (string, error)
+
 type Gitlab interface {
-	CreateEnvs(string, string, string) error
-	ListEnvs(string, string) error
-	DeleteEnvs(string, string) error
-	CreateProject(ProjectRequest) (int, error)
-	CreateSubgroup(string, string, *int) (int, error)
-	CreateGroup(string, string, string) (int, error)
-	BulkSettings(*chan string) error
-	Deprovionioning(string) error
-	UpdateMirroring() error
-	UpdateCleanUpPolicy(string) error
+	CreateEnvs(context.Context, string, string, string) error
+	ListEnvs(context.Context, string, string) error
+	DeleteEnvs(context.Context, string, string) error
+	CreateProject(context.Context, ProjectRequest) (int, error)
+	CreateSubgroup(context.Context, string, string, string) (int, error)
+	CreateGroup(context.Context, string, string, string) (int, error)
+	BulkSettings(context.Context, *chan string) error
+	Deprovionioning(context.Context, string) error
+	UpdateMirroring(context.Context) error
+	EnableMirroring(context.Context, string, string) error
+	VerifyMirrors(context.Context) error
+	UpdateCleanUpPolicy(context.Context, string) error
+	ListCleanUpPolicies(context.Context, string, int) error
+	AuditUsers(context.Context, AuditUsersRequest) error
+	ListMirrors(context.Context, time.Duration, bool) error
+	ListProjects(context.Context, ListProjectsRequest) error
+	ListGroups(context.Context, ListGroupsRequest) error
+	CompleteProjects(context.Context, string) ([]string, error)
+	CompleteGroups(context.Context, string) ([]string, error)
+	CompleteEnvironmentScopes(context.Context, string, string) ([]string, error)
+	ArchiveProjects(context.Context, ProjectLifecycleRequest) error
+	UnarchiveProjects(context.Context, ProjectLifecycleRequest) error
+	TransferProjects(context.Context, ProjectLifecycleRequest) error
+	DeleteProject(context.Context, DeleteRequest) error
+	DeleteGroup(context.Context, DeleteRequest) error
+	Backup(context.Context, BackupRequest) error
+	MigrateProject(context.Context, MigrateRequest) error
+	SyncFiles(context.Context, SyncFilesRequest) error
+	ListHooks(context.Context, ListHooksRequest) error
+	BulkEnvironments(context.Context, *chan string) error
 }
 
 type GitlabMirrorOptions struct {
-	Token     string `mapstructure:"token"`
-	ApiURL    string `mapstructure:"api_url"`
-	GroupPath string `mapstructure:"group_path"`
-	Username  string `mapstructure:"username"`
-	Password  string `mapstructure:"password"`
-	GroupID   int    `mapstructure:"group_id"`
+	Provider  string   `mapstructure:"provider"`
+	Token     string   `mapstructure:"token"`
+	ApiURL    string   `mapstructure:"api_url"`
+	GroupPath string   `mapstructure:"group_path"`
+	Username  string   `mapstructure:"username"`
+	Password  string   `mapstructure:"password"`
+	GroupID   int      `mapstructure:"group_id"`
+	URL       string   `mapstructure:"url"`
+	Projects  []int    `mapstructure:"projects"`
+	Groups    []string `mapstructure:"groups"`
+}
+
+// A mirror provider handle the target of the remote mirrors:
+// the creation of the repository and the URL used to push on it.
+type mirrorProvider interface {
+	verifyToken(ctx context.Context) error
+	setupRepository(ctx context.Context, name string, path string) error
+	pushURL(path string) string
+	repositoryExists(ctx context.Context, path string) (bool, error)
+	deleteRepository(ctx context.Context, path string) error
+	credentials() *url.Userinfo
+}
+
+type gitlabMirrorProvider struct {
+	options GitlabMirrorOptions
+}
+
+type githubMirrorProvider struct {
+	options GitlabMirrorOptions
+}
+
+type gitMirrorProvider struct {
+	options GitlabMirrorOptions
+}
+
+// The environments of the projects. The deployments on production
+// need the approvals provided, none when 0.
+type GitlabEnvironmentsOptions struct {
+	ProductionApprovals int `mapstructure:"production_approvals"`
+}
+
+type gitlabEnvironment struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+	Tier string `json:"tier"`
+}
+
+type gitlabProtectedEnvironment struct {
+	Name                  string              `json:"name"`
+	DeployAccessLevels    []gitlabAccessLevel `json:"deploy_access_levels"`
+	RequiredApprovalCount int                 `json:"required_approval_count"`
+}
+
+// The exports of the projects and groups
+// are downloaded in the directory provided.
+type GitlabBackupOptions struct {
+	Directory     string        `mapstructure:"directory"`
+	ExportTimeout time.Duration `mapstructure:"export_timeout"`
+	Parallel      int           `mapstructure:"parallel"`
+	Keep          int           `mapstructure:"keep"`
+	Retries       int           `mapstructure:"retries"`
+}
+
+// An export downloaded
+type gitlabBackupFile struct {
+	Path   string
+	Size   int64
+	SHA256 string
+}
+
+// The manifest written in the directory of each backup
+type gitlabBackupManifest struct {
+	Instance    string              `json:"instance"`
+	StartedAt   time.Time           `json:"started_at"`
+	CompletedAt time.Time           `json:"completed_at"`
+	Entries     []gitlabBackupEntry `json:"entries"`
+}
+
+type gitlabBackupEntry struct {
+	Kind     string `json:"kind"`
+	ID       int    `json:"id"`
+	FullPath string `json:"full_path"`
+	File     string `json:"file,omitempty"`
+	Size     int64  `json:"size,omitempty"`
+	SHA256   string `json:"sha256,omitempty"`
+	Status   string `json:"status"`
+	Attempts int    `json:"attempts"`
+	Error    string `json:"error,omitempty"`
+}
+
+// The projects and groups marked for deletion
+// have the date of the removal.
+type gitlabDeletionStatus struct {
+	MarkedForDeletionOn string `json:"marked_for_deletion_on"`
+}
+
+type gitlabExportStatus struct {
+	ExportStatus string `json:"export_status"`
+}
+
+// A webhook added to the projects, or to the groups with the group level.
+// The projects and groups are chosen by full path globs, all when empty.
+// Only the top level groups are taken without globs.
+type GitlabWebhookOptions struct {
+	Name            string   `mapstructure:"name"`
+	URL             string   `mapstructure:"url"`
+	Level           string   `mapstructure:"level"`
+	Events          []string `mapstructure:"events"`
+	Secret          string   `mapstructure:"secret"`
+	SSLVerification *bool    `mapstructure:"ssl_verification"`
+	Groups          []string `mapstructure:"groups"`
+}
+
+// A webhook of a project or a group.
+// The events are the flags ending with _events. Eg: push_events -> push
+type gitlabHook struct {
+	ID                    int    `json:"id"`
+	URL                   string `json:"url"`
+	Description           string `json:"description"`
+	EnableSSLVerification bool   `json:"enable_ssl_verification"`
+	Events                map[string]bool
+	// The description is available from GitLab 17.1
+	HasDescription bool
+}
+
+// The push rules of the projects. The rules are applied only when
+// at least one of them is provided.
+type GitlabPushRulesOptions struct {
+	CommitMessageRegex string `mapstructure:"commit_message_regex" json:"commit_message_regex"`
+	PreventSecrets     bool   `mapstructure:"prevent_secrets" json:"prevent_secrets"`
+	MemberCheck        bool   `mapstructure:"member_check" json:"member_check"`
+	MaxFileSize        int    `mapstructure:"max_file_size" json:"max_file_size"`
+}
+
+// A merge request approval rule of the projects.
+// The projects are chosen by group path globs, all when empty.
+// The approvers are usernames and group full paths.
+type GitlabApprovalRuleOptions struct {
+	Name              string   `mapstructure:"name"`
+	ApprovalsRequired int      `mapstructure:"approvals_required"`
+	Users             []string `mapstructure:"users"`
+	ApproverGroups    []string `mapstructure:"approver_groups"`
+	ProtectedBranches []string `mapstructure:"protected_branches"`
+	Groups            []string `mapstructure:"groups"`
+}
+
+type gitlabApprovalRuleResponse struct {
+	ID                int                  `json:"id"`
+	Name              string               `json:"name"`
+	RuleType          string               `json:"rule_type"`
+	ApprovalsRequired int                  `json:"approvals_required"`
+	Users             []gitlabEntityWithID `json:"users"`
+	Groups            []gitlabEntityWithID `json:"groups"`
+	ProtectedBranches []gitlabEntityWithID `json:"protected_branches"`
+}
+
+type gitlabApprovalRuleRequest struct {
+	Name               string `json:"name"`
+	ApprovalsRequired  int    `json:"approvals_required"`
+	UserIDs            []int  `json:"user_ids"`
+	GroupIDs           []int  `json:"group_ids"`
+	ProtectedBranchIDs []int  `json:"protected_branch_ids"`
 }
 
 type GitlabExclusionsConfig struct {
 	CleanupPolicies []int `mapstructure:"cleanup_policies"`
 }
 
+type GitlabCleanupPolicy struct {
+	Cadence       string `mapstructure:"cadence" json:"cadence"`
+	Enabled       bool   `mapstructure:"enabled" json:"enabled"`
+	KeepN         int    `mapstructure:"keep_n" json:"keep_n"`
+	OlderThan     string `mapstructure:"older_than" json:"older_than"`
+	NameRegex     string `mapstructure:"name_regex" json:"name_regex"`
+	NameRegexKeep string `mapstructure:"name_regex_keep" json:"name_regex_keep"`
+}
+
+type GitlabCleanupPolicyRule struct {
+	Profile  string   `mapstructure:"profile"`
+	Projects []int    `mapstructure:"projects"`
+	Groups   []string `mapstructure:"groups"`
+	Topics   []string `mapstructure:"topics"`
+}
+
+type GitlabCleanupPoliciesConfig struct {
+	Default  string                         `mapstructure:"default"`
+	Profiles map[string]GitlabCleanupPolicy `mapstructure:"profiles"`
+	Rules    []GitlabCleanupPolicyRule      `mapstructure:"rules"`
+}
+
 type gitlabCreateMirrorRequest struct {
 	Enabled               bool   `json:"enabled"`
 	URL                   string `json:"url"`
 	OnlyProtectedBranched bool   `json:"only_protected_branches"`
+	KeepDivergentRefs     bool   `json:"keep_divergent_refs"`
+	MirrorBranchRegex     string `json:"mirror_branch_regex,omitempty"`
+	AuthMethod            string `json:"auth_method,omitempty"`
+}
+
+type githubCreateRepositoryRequest struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Private     bool   `json:"private"`
 }
 
 type gitlabMirrorResponse struct {
-	ID      int    `json:"id"`
-	Enabled bool   `json:"enabled"`
-	Url     string `json:"url"`
+	ID                     int    `json:"id"`
+	Enabled                bool   `json:"enabled"`
+	Url                    string `json:"url"`
+	OnlyProtectedBranches  bool   `json:"only_protected_branches"`
+	KeepDivergentRefs      bool   `json:"keep_divergent_refs"`
+	MirrorBranchRegex      string `json:"mirror_branch_regex"`
+	UpdateStatus           string `json:"update_status"`
+	LastUpdateAt           string `json:"last_update_at"`
+	LastSuccessfulUpdateAt string `json:"last_successful_update_at"`
+	LastError              string `json:"last_error"`
+}
+
+type gitlabMirrorUpdateResult struct {
+	project string
+	mirror  string
+	status  string
+	message string
+}
+
+type gitlabLifecycleResult struct {
+	project  string
+	status   string
+	messages []string
+}
+
+// The findings of the pre-checks of a lifecycle operation.
+// The warnings stop the operation unless forced.
+type gitlabLifecycleChecks struct {
+	notes    []string
+	warnings []string
+}
+
+// A lifecycle operation on a project.
+// skip returns the reason to leave the project untouched, if any.
+type gitlabLifecycleOperation struct {
+	skip  func(gitlabProjectResponse) string
+	check func(context.Context, gitlabProjectResponse) (gitlabLifecycleChecks, error)
+	apply func(context.Context, gitlabProjectResponse) (string, string, error)
+}
+
+type gitlabMirrorVerifyResult struct {
+	project       string
+	mirrorProject string
+	status        string
+	details       []string
 }
 
+// The signature shared by the requestWithHeaders methods of the gitlab
+// instances. Useful to run the same call on both the primary and the mirror.
+type gitlabRequestFunc func(context.Context, string, string, any, map[string]string) ([]byte, http.Header, error)
+
 type gitlabDefaultUser struct {
 	tipology   string
 	permission int
@@ -70,8 +402,12 @@ type gitlabCreateSubgroupRequest struct {
 
 type gitlabSubgroupResponse struct {
 	ID                   int    `json:"id"`
+	Name                 string `json:"name"`
+	Path                 string `json:"path"`
+	FullPath             string `json:"full_path"`
 	Visibility           string `json:"visibility"`
 	RequestAccessEnabled bool   `json:"request_access_enabled"`
+	ParentID             int    `json:"parent_id"`
 }
 
 type gitlabCreateProjectRequest struct {
@@ -108,6 +444,39 @@ type gitlabCreateProjectRequest struct {
 	PackageRegistryAccessLevel       string `json:"package_registry_access_level"`
 	PackageRegistryEnabled           bool   `json:"package_registry_enabled"`
 	OnlyAllowMergeIfPipelineSucceeds bool   `json:"only_allow_merge_if_pipeline_succeeds"`
+	TemplateProjectID                int    `json:"template_project_id,omitempty"`
+	UseCustomTemplate                bool   `json:"use_custom_template,omitempty"`
+	ImportURL                        string `json:"import_url,omitempty"`
+}
+
+// A commit with the files provided.
+// The content of the files is encoded in base64.
+type gitlabCreateCommitRequest struct {
+	Branch        string               `json:"branch"`
+	StartBranch   string               `json:"start_branch,omitempty"`
+	Force         bool                 `json:"force,omitempty"`
+	CommitMessage string               `json:"commit_message"`
+	Actions       []gitlabCommitAction `json:"actions"`
+}
+
+// A file of a local template directory
+type gitlabTemplateFile struct {
+	Path       string
+	Content    []byte
+	Executable bool
+}
+
+type gitlabMergeRequest struct {
+	IID    int    `json:"iid"`
+	WebURL string `json:"web_url"`
+}
+
+type gitlabCommitAction struct {
+	Action          string `json:"action"`
+	FilePath        string `json:"file_path"`
+	Content         string `json:"content"`
+	Encoding        string `json:"encoding"`
+	ExecuteFilemode bool   `json:"execute_filemode,omitempty"`
 }
 
 type gitlabAddUserToGroupRequest struct {
@@ -117,7 +486,43 @@ type gitlabAddUserToGroupRequest struct {
 }
 
 type gitlabProjectResponse struct {
-	ID int `json:"id"`
+	ID                        int                      `json:"id"`
+	Name                      string                   `json:"name"`
+	Path                      string                   `json:"path"`
+	PathWithNamespace         string                   `json:"path_with_namespace"`
+	Topics                    []string                 `json:"topics"`
+	CreatedAt                 string                   `json:"created_at"`
+	Visibility                string                   `json:"visibility"`
+	DefaultBranch             string                   `json:"default_branch"`
+	LastActivityAt            string                   `json:"last_activity_at"`
+	Archived                  bool                     `json:"archived"`
+	SharedRunnersEnabled      bool                     `json:"shared_runners_enabled"`
+	HTTPURLToRepo             string                   `json:"http_url_to_repo"`
+	ContainerExpirationPolicy *GitlabCleanupPolicy     `json:"container_expiration_policy"`
+	Statistics                *gitlabProjectStatistics `json:"statistics"`
+}
+
+type gitlabProjectStatistics struct {
+	StorageSize           int64 `json:"storage_size"`
+	RepositorySize        int64 `json:"repository_size"`
+	ContainerRegistrySize int64 `json:"container_registry_size"`
+}
+
+type gitlabRegistryRepository struct {
+	ID        int    `json:"id"`
+	Path      string `json:"path"`
+	TagsCount int    `json:"tags_count"`
+	Size      int64  `json:"size"`
+}
+
+type gitlabRegistryUsage struct {
+	project      gitlabProjectResponse
+	profile      string
+	standard     string
+	repositories int
+	tags         int
+	size         int64
+	dailyAverage float64
 }
 
 type gitlabProjectListVariable struct {
@@ -134,10 +539,48 @@ type gitlabEntityWithID struct {
 	ID int `json:"id"`
 }
 
+type gitlabCommitResponse struct {
+	ID string `json:"id"`
+}
+
 type gitlabBranchResponse struct {
-	ID      int    `json:"id"`
-	Name    string `json:"name"`
-	Default bool   `json:"default"`
+	ID        int                  `json:"id"`
+	Name      string               `json:"name"`
+	Default   bool                 `json:"default"`
+	Protected bool                 `json:"protected"`
+	Commit    gitlabCommitResponse `json:"commit"`
+}
+
+type gitlabTagResponse struct {
+	Name   string               `json:"name"`
+	Commit gitlabCommitResponse `json:"commit"`
+}
+
+type gitlabProtectedBranchResponse struct {
+	ID                int                 `json:"id"`
+	Name              string              `json:"name"`
+	PushAccessLevels  []gitlabAccessLevel `json:"push_access_levels"`
+	MergeAccessLevels []gitlabAccessLevel `json:"merge_access_levels"`
+	AllowForcePush    bool                `json:"allow_force_push"`
+}
+
+type gitlabProtectedTagResponse struct {
+	Name               string              `json:"name"`
+	CreateAccessLevels []gitlabAccessLevel `json:"create_access_levels"`
+}
+
+// An access level of the protected branches and tags.
+// The levels of a user or a group have the ID set.
+type gitlabAccessLevel struct {
+	ID          int  `json:"id"`
+	AccessLevel int  `json:"access_level"`
+	UserID      *int `json:"user_id"`
+	GroupID     *int `json:"group_id"`
+}
+
+type gitlabImportStatus struct {
+	ImportStatus string `json:"import_status"`
+	ImportError  string `json:"import_error"`
 }
 
 type gitlabSetupBranchRequest struct {
@@ -147,8 +590,37 @@ type gitlabSetupBranchRequest struct {
 }
 
 type gitlabUser struct {
-	ID   int    `json:"id"`
-	Note string `json:"note"`
+	ID               int    `json:"id"`
+	Username         string `json:"username"`
+	Name             string `json:"name"`
+	State            string `json:"state"`
+	Note             string `json:"note"`
+	CreatedAt        string `json:"created_at"`
+	LastActivityOn   string `json:"last_activity_on"`
+	TwoFactorEnabled bool   `json:"two_factor_enabled"`
+	External         bool   `json:"external"`
+	IsAdmin          bool   `json:"is_admin"`
+	Bot              bool   `json:"bot"`
+}
+
+type gitlabUserMembership struct {
+	SourceID    int    `json:"source_id"`
+	SourceName  string `json:"source_name"`
+	SourceType  string `json:"source_type"`
+	AccessLevel int    `json:"access_level"`
+}
+
+type gitlabPersonalAccessToken struct {
+	ID        int     `json:"id"`
+	Name      string  `json:"name"`
+	Active    bool    `json:"active"`
+	Revoked   bool    `json:"revoked"`
+	ExpiresAt *string `json:"expires_at"`
+}
+
+type gitlabUserAudit struct {
+	user    gitlabUser
+	reasons []string
 }
 
 type gitlabCreateEnvRequest struct {
@@ -167,7 +639,108 @@ type ProjectRequest struct {
 	DefaultBranch string
 	Mirror        bool
 	SharedRunners bool
-	Group         int
+	Group         string
+	FromTemplate  string
+	ImportURL     string
+	Skeleton      string
+}
+
+type ListProjectsRequest struct {
+	Group      string
+	Search     string
+	Visibility string
+	Topic      string
+	Archived   string
+	Mirror     bool
+	WithMirror bool
+	Output     string
+}
+
+type ListGroupsRequest struct {
+	Parent   string
+	Search   string
+	TopLevel bool
+	Output   string
+}
+
+// The rows of the list commands.
+// These are encoded as they are in json and yaml outputs.
+type gitlabProjectRow struct {
+	ID            int    `json:"id" yaml:"id"`
+	FullPath      string `json:"full_path" yaml:"full_path"`
+	Visibility    string `json:"visibility" yaml:"visibility"`
+	DefaultBranch string `json:"default_branch" yaml:"default_branch"`
+	LastActivity  string `json:"last_activity" yaml:"last_activity"`
+	Archived      bool   `json:"archived" yaml:"archived"`
+	MirrorEnabled *bool  `json:"mirror_enabled,omitempty" yaml:"mirror_enabled,omitempty"`
+	SharedRunners bool   `json:"shared_runners" yaml:"shared_runners"`
+}
+
+type gitlabGroupRow struct {
+	ID         int    `json:"id" yaml:"id"`
+	FullPath   string `json:"full_path" yaml:"full_path"`
+	Name       string `json:"name" yaml:"name"`
+	Visibility string `json:"visibility" yaml:"visibility"`
+}
+
+type ProjectLifecycleRequest struct {
+	Project  string
+	Selector string
+	ToGroup  string
+	DryRun   bool
+	Force    bool
+}
+
+type DeleteRequest struct {
+	Reference         string
+	PermanentlyRemove bool
+}
+
+type BackupRequest struct {
+	Project  string
+	Selector string
+	Group    string
+	Parallel int
+	Keep     int
+}
+
+type MigrateRequest struct {
+	Project       string
+	To            string
+	Group         string
+	RepointMirror bool
+}
+
+type SyncFilesRequest struct {
+	Project  string
+	Selector string
+	Template string
+	Branch   string
+	DryRun   bool
+}
+
+type ListHooksRequest struct {
+	Project  string
+	Selector string
+	All      bool
+	Output   string
+}
+
+type gitlabHookRow struct {
+	Kind     string `json:"kind" yaml:"kind"`
+	FullPath string `json:"full_path" yaml:"full_path"`
+	URL      string `json:"url" yaml:"url"`
+	Events   string `json:"events" yaml:"events"`
+	SSL      bool   `json:"ssl_verification" yaml:"ssl_verification"`
+	Standard bool   `json:"standard" yaml:"standard"`
+}
+
+type AuditUsersRequest struct {
+	InactiveDays int
+	Reasons      []string
+	Block        bool
+	Deactivate   bool
+	Force        bool
 }
 
 var defaultGitlabCreatePayload = gitlabCreateProjectRequest{
@@ -248,15 +821,13 @@ var defaultProjectStagingSettings = gitlabSetupBranchRequest{
 	MergeAccessLevel: 30,
 }
 
-var defaultCleanUpPolicy = map[string]interface{}{
-	"container_expiration_policy_attributes": map[string]interface{}{
-		"cadence":         "7d",
-		"enabled":         true,
-		"keep_n":          1,
-		"older_than":      "7d",
-		"name_regex":      ".*",
-		"name_regex_keep": "",
-	},
+var defaultCleanUpPolicy = GitlabCleanupPolicy{
+	Cadence:       "7d",
+	Enabled:       true,
+	KeepN:         1,
+	OlderThan:     "7d",
+	NameRegex:     ".*",
+	NameRegexKeep: "",
 }
 
 var defaultProtectedTags = map[string]interface{}{
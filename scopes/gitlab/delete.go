@@ -0,0 +1,259 @@
+package gitlab
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"opsi/helpers"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// The remote mirror of the project to delete with it.
+// The repository is deleted only on the mirror target
+// configured for the project.
+type gitlabMirrorRepository struct {
+	provider mirrorProvider
+	path     string
+	url      string
+}
+
+func (g *gitlab) projectMirrorRepository(ctx context.Context, project gitlabProjectResponse) (*gitlabMirrorRepository, error) {
+	mirror, hasMirroring, err := g.checkMirroringExistence(ctx, project.ID)
+	if err != nil || !hasMirroring {
+		return nil, err
+	}
+
+	provider, err := g.mirrorProviderFor(project)
+	if err != nil {
+		return nil, err
+	}
+
+	path, ok := mirrorProjectPath(mirror.Url)
+	if !ok || !sameHost(mirror.Url, provider.pushURL(project.Path)) {
+		fmt.Printf("The mirror %s is not a configured target, delete it by hand\n", maskURLCredentials(mirror.Url))
+		return nil, nil
+	}
+
+	return &gitlabMirrorRepository{
+		provider: provider,
+		path:     path,
+		url:      maskURLCredentials(mirror.Url),
+	}, nil
+}
+
+// Delete the mirror repositories, reporting the failures
+// without stopping: the primary projects are already deleted.
+func deleteMirrorRepositories(ctx context.Context, repositories []gitlabMirrorRepository) int {
+	failed := 0
+	for _, repository := range repositories {
+		err := repository.provider.deleteRepository(ctx, repository.path)
+		if err != nil {
+			failed++
+			fmt.Printf("The mirror %s is not deleted: %s\n", repository.url, err.Error())
+			continue
+		}
+
+		fmt.Printf("The mirror %s is deleted\n", repository.url)
+	}
+
+	return failed
+}
+
+// Delete the project or group. With the delayed deletion enabled the entity
+// is only marked for deletion: the permanent removal deletes it right away.
+func (g *gitlab) deleteEntity(ctx context.Context, endpoint string, fullPath string, permanentlyRemove bool) error {
+	_, err := g.request(ctx, "DELETE", endpoint, nil, nil)
+	if err != nil {
+		return err
+	}
+
+	if !permanentlyRemove {
+		return nil
+	}
+
+	// The entity is already removed when the delayed deletion is disabled
+	_, err = g.request(ctx, "DELETE", endpoint, nil, map[string]string{
+		"permanently_remove": "true",
+		"full_path":          fullPath,
+	})
+	if helpers.IsNotFound(err) {
+		return nil
+	}
+
+	return err
+}
+
+// Check if the entity is only marked for deletion and return the date
+// of the removal. The date is empty when the entity is deleted: the
+// deletion runs in background, so the entity can still be found for a while.
+func (g *gitlab) markedForDeletion(ctx context.Context, endpoint string, queryMap map[string]string) (string, error) {
+	response, err := g.request(ctx, "GET", endpoint, nil, queryMap)
+	if helpers.IsNotFound(err) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+
+	var status gitlabDeletionStatus
+	err = json.Unmarshal(response, &status)
+	return status.MarkedForDeletionOn, err
+}
+
+func (g *gitlab) DeleteProject(ctx context.Context, options DeleteRequest) error {
+	// Never delete on a stale inventory
+	ctx = helpers.WithoutCache(ctx)
+
+	project, err := g.resolveProject(ctx, options.Reference)
+	if err != nil {
+		return err
+	}
+
+	directory, err := g.backupDirectory()
+	if err != nil {
+		return err
+	}
+
+	mirror, err := g.projectMirrorRepository(ctx, project)
+	if err != nil {
+		return err
+	}
+
+	// Show what is going to be deleted
+	fmt.Printf("The project %s (#%d) will be deleted\n", project.PathWithNamespace, project.ID)
+	fmt.Printf("The backup will be saved in %s\n", directory)
+	if mirror != nil {
+		fmt.Printf("The mirror %s will be deleted\n", mirror.url)
+	}
+	if options.PermanentlyRemove {
+		fmt.Println("The project will be removed permanently, it cannot be restored")
+	}
+
+	helpers.ConfirmValue(project.PathWithNamespace)
+
+	// Never delete without a backup
+	_, err = g.exportProject(ctx, project, directory)
+	if err != nil {
+		return fmt.Errorf("the project is not deleted, the backup is failed: %s", err.Error())
+	}
+
+	endpoint := fmt.Sprintf("/projects/%d", project.ID)
+	err = g.deleteEntity(ctx, endpoint, project.PathWithNamespace, options.PermanentlyRemove)
+	if err != nil {
+		return err
+	}
+
+	markedOn, err := g.markedForDeletion(ctx, endpoint, nil)
+	if err != nil {
+		return err
+	}
+
+	if markedOn != "" {
+		fmt.Printf("The project %s is marked for deletion and it will be removed on %s\n", project.PathWithNamespace, markedOn)
+	} else {
+		fmt.Printf("The project %s is deleted\n", project.PathWithNamespace)
+	}
+
+	// Nothing removes the mirror later: the restored
+	// project can be mirrored again from the backup
+	if mirror != nil && deleteMirrorRepositories(ctx, []gitlabMirrorRepository{*mirror}) > 0 {
+		return fmt.Errorf("the mirror of %s is not deleted", project.PathWithNamespace)
+	}
+
+	return nil
+}
+
+func (g *gitlab) DeleteGroup(ctx context.Context, options DeleteRequest) error {
+	// The projects created outside opsi must be exported too
+	ctx = helpers.WithoutCache(ctx)
+
+	group, err := g.resolveGroup(ctx, options.Reference)
+	if err != nil {
+		return err
+	}
+
+	baseDirectory, err := g.backupDirectory()
+	if err != nil {
+		return err
+	}
+
+	// All the exports of the group are kept together
+	directory := filepath.Join(baseDirectory, strings.TrimSuffix(exportFileName(group.FullPath, time.Now()), ".tar.gz"))
+
+	projects, err := g.listGroupProjects(ctx, group.ID)
+	if err != nil {
+		return err
+	}
+
+	mirrors := []gitlabMirrorRepository{}
+	for _, project := range projects {
+		mirror, err := g.projectMirrorRepository(ctx, project)
+		if err != nil {
+			return err
+		}
+
+		if mirror != nil {
+			mirrors = append(mirrors, *mirror)
+		}
+	}
+
+	// Show what is going to be deleted
+	fmt.Printf("The group %s (#%d) and its %d projects will be deleted\n", group.FullPath, group.ID, len(projects))
+	fmt.Printf("The backups will be saved in %s\n", directory)
+	for _, mirror := range mirrors {
+		fmt.Printf("The mirror %s will be deleted\n", mirror.url)
+	}
+	if options.PermanentlyRemove {
+		fmt.Println("The group will be removed permanently, it cannot be restored")
+	}
+
+	helpers.ConfirmValue(group.FullPath)
+
+	// Never delete without a backup of each project
+	_, err = g.exportGroup(ctx, group, directory)
+	if err != nil {
+		return fmt.Errorf("the group is not deleted, the backup is failed: %s", err.Error())
+	}
+
+	for index, project := range projects {
+		if ctx.Err() != nil {
+			helpers.Interrupted(ctx, index, len(projects), "projects exported")
+			return fmt.Errorf("the group is not deleted, the backup is not completed")
+		}
+
+		_, err = g.exportProject(ctx, project, directory)
+		if err != nil {
+			return fmt.Errorf("the group is not deleted, the backup of %s is failed: %s", project.PathWithNamespace, err.Error())
+		}
+	}
+
+	endpoint := fmt.Sprintf("/groups/%d", group.ID)
+	err = g.deleteEntity(ctx, endpoint, group.FullPath, options.PermanentlyRemove)
+	if err != nil {
+		return err
+	}
+
+	markedOn, err := g.markedForDeletion(ctx, endpoint, map[string]string{
+		"with_projects": "false",
+	})
+	if err != nil {
+		return err
+	}
+
+	if markedOn != "" {
+		fmt.Printf("The group %s is marked for deletion and it will be removed on %s\n", group.FullPath, markedOn)
+	} else {
+		fmt.Printf("The group %s is deleted\n", group.FullPath)
+	}
+
+	// Nothing removes the mirrors later: the restored
+	// projects can be mirrored again from the backups
+	failed := deleteMirrorRepositories(ctx, mirrors)
+	if failed > 0 {
+		return fmt.Errorf("%d mirrors of %s are not deleted", failed, group.FullPath)
+	}
+
+	return nil
+}
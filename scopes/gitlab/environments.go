@@ -0,0 +1,182 @@
+package gitlab
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"opsi/helpers"
+)
+
+// Create the environments of the branch model missing in the project.
+// The environments have the same name of the scopes of the variables.
+func (g *gitlab) ensureEnvironments(ctx context.Context, projectID int) ([]string, error) {
+	changes := []string{}
+	endpoint := fmt.Sprintf("/projects/%d/environments", projectID)
+
+	environments, err := collect[gitlabEnvironment](ctx, g.requestWithHeaders, endpoint, nil)
+	if err != nil {
+		return changes, err
+	}
+
+	for _, name := range []string{gitlabEnvironmentStaging, gitlabEnvironmentProduction} {
+		found := false
+		for _, environment := range environments {
+			found = found || environment.Name == name
+		}
+
+		if found {
+			continue
+		}
+
+		_, err = g.request(ctx, "POST", endpoint, map[string]string{
+			"name": name,
+			"tier": name,
+		}, nil)
+		if err != nil && !isAlreadyExists(err) {
+			return changes, err
+		}
+
+		changes = append(changes, "environment "+name+" created")
+	}
+
+	return changes, nil
+}
+
+func isMaintainersLevel(level gitlabAccessLevel) bool {
+	return level.AccessLevel == gitlabMaintainerPermission && level.UserID == nil && level.GroupID == nil
+}
+
+// Check if only the maintainers can deploy on the environment
+// with the approvals provided
+func (g *gitlab) productionProtected(environment gitlabProtectedEnvironment) bool {
+	return environment.RequiredApprovalCount == g.environments.ProductionApprovals &&
+		len(environment.DeployAccessLevels) == 1 &&
+		isMaintainersLevel(environment.DeployAccessLevels[0])
+}
+
+// The deploy access levels to send for updating the protection: the
+// other levels are destroyed and the maintainers one is added if missing
+func maintainersDeployAccessLevels(levels []gitlabAccessLevel) []map[string]interface{} {
+	payload := []map[string]interface{}{}
+	hasMaintainers := false
+	for _, level := range levels {
+		if isMaintainersLevel(level) && !hasMaintainers {
+			hasMaintainers = true
+			continue
+		}
+
+		payload = append(payload, map[string]interface{}{
+			"id":       level.ID,
+			"_destroy": true,
+		})
+	}
+
+	if !hasMaintainers {
+		payload = append(payload, map[string]interface{}{
+			"access_level": gitlabMaintainerPermission,
+		})
+	}
+
+	return payload
+}
+
+// Protect the production environment: the deployments are
+// allowed only to the maintainers, with the approvals provided.
+// An existing protection is updated in place, so production is
+// never left unprotected when the update is refused.
+func (g *gitlab) protectProduction(ctx context.Context, projectID int) ([]string, error) {
+	endpoint := fmt.Sprintf("/projects/%d/protected_environments", projectID)
+
+	response, err := g.request(ctx, "GET", endpoint+"/"+gitlabEnvironmentProduction, nil, nil)
+	if err != nil && !helpers.IsNotFound(err) {
+		return nil, tierUnavailable("protected environments", err)
+	}
+
+	if err == nil {
+		var environment gitlabProtectedEnvironment
+		err = json.Unmarshal(response, &environment)
+		if err != nil {
+			return nil, err
+		}
+
+		if g.productionProtected(environment) {
+			return nil, nil
+		}
+
+		_, err = g.request(ctx, "PUT", endpoint+"/"+gitlabEnvironmentProduction, map[string]interface{}{
+			"deploy_access_levels":    maintainersDeployAccessLevels(environment.DeployAccessLevels),
+			"required_approval_count": g.environments.ProductionApprovals,
+		}, nil)
+		if err != nil {
+			return nil, fmt.Errorf("the protection of production was not updated: %s", err.Error())
+		}
+
+		return []string{"environment production protection updated"}, nil
+	}
+
+	payload := map[string]interface{}{
+		"name": gitlabEnvironmentProduction,
+		"deploy_access_levels": []map[string]int{
+			{"access_level": gitlabMaintainerPermission},
+		},
+	}
+	if g.environments.ProductionApprovals > 0 {
+		payload["required_approval_count"] = g.environments.ProductionApprovals
+	}
+
+	_, err = g.request(ctx, "POST", endpoint, payload, nil)
+	if err != nil {
+		return nil, tierUnavailable("protected environments", err)
+	}
+
+	return []string{"environment production protected"}, nil
+}
+
+// Create the environments and protect the production one.
+// The protection not available on the tier of the instance is
+// reported without failing.
+func (g *gitlab) setupEnvironments(ctx context.Context, projectID int) ([]string, error) {
+	changes, err := g.ensureEnvironments(ctx, projectID)
+	if err != nil {
+		return changes, err
+	}
+
+	protection, err := g.protectProduction(ctx, projectID)
+	changes = append(changes, protection...)
+	if errors.Is(err, errTierUnavailable) {
+		return append(changes, err.Error()+", skipped"), nil
+	}
+
+	return changes, err
+}
+
+func (g *gitlab) BulkEnvironments(ctx context.Context, channel *chan string) error {
+	projects, err := g.listProjects(ctx)
+	if err != nil {
+		return err
+	}
+
+	for index, project := range projects {
+		// Don't touch other projects once interrupted
+		if ctx.Err() != nil {
+			return helpers.Interrupted(ctx, index, len(projects), "projects")
+		}
+
+		// The archived projects are read only
+		if project.Archived {
+			continue
+		}
+
+		changes, err := g.setupEnvironments(ctx, project.ID)
+		for _, change := range changes {
+			*channel <- fmt.Sprintf("Project #%d: %s", project.ID, change)
+		}
+
+		if err != nil {
+			*channel <- fmt.Sprintf("Error on setup environments for project #%d: %s", project.ID, err.Error())
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,71 @@
+package gitlab
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestProductionProtected(t *testing.T) {
+	userID := 7
+	g := &gitlab{environments: GitlabEnvironmentsOptions{ProductionApprovals: 1}}
+
+	tests := []struct {
+		name        string
+		environment gitlabProtectedEnvironment
+		protected   bool
+	}{
+		{"maintainers", gitlabProtectedEnvironment{RequiredApprovalCount: 1, DeployAccessLevels: []gitlabAccessLevel{{AccessLevel: gitlabMaintainerPermission}}}, true},
+		{"approvals", gitlabProtectedEnvironment{DeployAccessLevels: []gitlabAccessLevel{{AccessLevel: gitlabMaintainerPermission}}}, false},
+		{"developers", gitlabProtectedEnvironment{RequiredApprovalCount: 1, DeployAccessLevels: []gitlabAccessLevel{{AccessLevel: gitlabDeveloperPermission}}}, false},
+		{"user", gitlabProtectedEnvironment{RequiredApprovalCount: 1, DeployAccessLevels: []gitlabAccessLevel{{AccessLevel: gitlabMaintainerPermission, UserID: &userID}}}, false},
+		{"more levels", gitlabProtectedEnvironment{RequiredApprovalCount: 1, DeployAccessLevels: []gitlabAccessLevel{{AccessLevel: gitlabMaintainerPermission}, {AccessLevel: gitlabDeveloperPermission}}}, false},
+		{"no levels", gitlabProtectedEnvironment{RequiredApprovalCount: 1}, false},
+	}
+
+	for _, test := range tests {
+		protected := g.productionProtected(test.environment)
+		if protected != test.protected {
+			t.Errorf("%s: productionProtected = %v; want %v", test.name, protected, test.protected)
+		}
+	}
+}
+
+func TestMaintainersDeployAccessLevels(t *testing.T) {
+	userID := 7
+
+	tests := []struct {
+		name    string
+		levels  []gitlabAccessLevel
+		payload []map[string]interface{}
+	}{
+		{
+			name:   "maintainers missing",
+			levels: []gitlabAccessLevel{{ID: 1, AccessLevel: gitlabDeveloperPermission}},
+			payload: []map[string]interface{}{
+				{"id": 1, "_destroy": true},
+				{"access_level": gitlabMaintainerPermission},
+			},
+		},
+		{
+			name:   "maintainers kept",
+			levels: []gitlabAccessLevel{{ID: 1, AccessLevel: gitlabMaintainerPermission}, {ID: 2, AccessLevel: gitlabMaintainerPermission, UserID: &userID}},
+			payload: []map[string]interface{}{
+				{"id": 2, "_destroy": true},
+			},
+		},
+		{
+			name:   "no levels",
+			levels: []gitlabAccessLevel{},
+			payload: []map[string]interface{}{
+				{"access_level": gitlabMaintainerPermission},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		payload := maintainersDeployAccessLevels(test.levels)
+		if !reflect.DeepEqual(payload, test.payload) {
+			t.Errorf("%s: maintainersDeployAccessLevels = %v; want %v", test.name, payload, test.payload)
+		}
+	}
+}
@@ -0,0 +1,182 @@
+package gitlab
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"opsi/helpers"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// The directory of the backups.
+// Eg: ~/opsi-backups
+func (g *gitlab) backupDirectory() (string, error) {
+	directory := g.backup.Directory
+	if directory != "" && !strings.HasPrefix(directory, "~") {
+		return directory, nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+
+	if directory == "" {
+		return filepath.Join(home, "opsi-backups"), nil
+	}
+
+	return filepath.Join(home, strings.TrimPrefix(directory, "~")), nil
+}
+
+func (g *gitlab) exportTimeout() time.Duration {
+	if g.backup.ExportTimeout > 0 {
+		return g.backup.ExportTimeout
+	}
+
+	return gitlabDefaultExportTimeout
+}
+
+// The name of the export file.
+// Eg: client-x/website -> client-x_website_20240131-153000.tar.gz
+func exportFileName(fullPath string, at time.Time) string {
+	return fmt.Sprintf("%s_%s.tar.gz", strings.ReplaceAll(fullPath, "/", "_"), at.Format("20060102-150405"))
+}
+
+// Export the project, wait for the export to complete
+// and download the archive in the directory provided.
+func (g *gitlab) exportProject(ctx context.Context, project gitlabProjectResponse, directory string) (gitlabBackupFile, error) {
+	fmt.Printf("Exporting %s...\n", project.PathWithNamespace)
+
+	endpoint := fmt.Sprintf("/projects/%d/export", project.ID)
+	_, err := g.request(ctx, "POST", endpoint, nil, nil)
+	if err != nil {
+		return gitlabBackupFile{}, err
+	}
+
+	// Wait for the export
+	waitCtx, cancel := context.WithTimeout(ctx, g.exportTimeout())
+	defer cancel()
+
+	for {
+		response, err := g.request(waitCtx, "GET", endpoint, nil, nil)
+		if err != nil {
+			return gitlabBackupFile{}, g.exportError(ctx, project.PathWithNamespace, err)
+		}
+
+		var status gitlabExportStatus
+		err = json.Unmarshal(response, &status)
+		if err != nil {
+			return gitlabBackupFile{}, err
+		}
+
+		if status.ExportStatus == gitlabExportFinished {
+			break
+		}
+
+		if status.ExportStatus == gitlabExportFailed {
+			return gitlabBackupFile{}, fmt.Errorf("the export of %s is failed", project.PathWithNamespace)
+		}
+
+		if !helpers.Sleep(waitCtx, gitlabExportPollInterval) {
+			return gitlabBackupFile{}, g.exportError(ctx, project.PathWithNamespace, waitCtx.Err())
+		}
+	}
+
+	return g.downloadExport(ctx, endpoint+"/download", filepath.Join(directory, exportFileName(project.PathWithNamespace, time.Now())))
+}
+
+// Export the group, without the projects.
+// The group exports have no status, the download is not found
+// until the export is completed, or it is the archive of the
+// previous export: the new archive has a different file name.
+func (g *gitlab) exportGroup(ctx context.Context, group gitlabSubgroupResponse, directory string) (gitlabBackupFile, error) {
+	fmt.Printf("Exporting %s...\n", group.FullPath)
+
+	endpoint := fmt.Sprintf("/groups/%d/export", group.ID)
+	previous, err := g.groupExportArchive(ctx, endpoint+"/download")
+	if err != nil {
+		return gitlabBackupFile{}, err
+	}
+
+	_, err = g.request(ctx, "POST", endpoint, nil, nil)
+	if err != nil {
+		return gitlabBackupFile{}, err
+	}
+
+	waitCtx, cancel := context.WithTimeout(ctx, g.exportTimeout())
+	defer cancel()
+
+	for {
+		if !helpers.Sleep(waitCtx, gitlabExportPollInterval) {
+			return gitlabBackupFile{}, g.exportError(ctx, group.FullPath, waitCtx.Err())
+		}
+
+		archive, err := g.groupExportArchive(waitCtx, endpoint+"/download")
+		if err != nil {
+			return gitlabBackupFile{}, g.exportError(ctx, group.FullPath, err)
+		}
+
+		if archive != "" && archive != previous {
+			break
+		}
+	}
+
+	return g.downloadExport(ctx, endpoint+"/download", filepath.Join(directory, exportFileName(group.FullPath, time.Now())))
+}
+
+// The file name of the group export available for the download,
+// empty if there is no export.
+// Eg: 2024-01-31_15-30-123_client-x_export.tar.gz
+func (g *gitlab) groupExportArchive(ctx context.Context, endpoint string) (string, error) {
+	_, headers, err := g.requestWithHeaders(ctx, http.MethodHead, endpoint, nil, nil)
+	if helpers.IsNotFound(err) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+
+	_, params, err := mime.ParseMediaType(headers.Get("Content-Disposition"))
+	if err != nil || params["filename"] == "" {
+		return "", fmt.Errorf("the export archive has no file name, it cannot be told apart from the previous one")
+	}
+
+	return params["filename"], nil
+}
+
+// Download the export and print the size and checksum
+func (g *gitlab) downloadExport(ctx context.Context, endpoint string, destination string) (gitlabBackupFile, error) {
+	size, checksum, err := helpers.Download(ctx, g.apiURL+endpoint, map[string]string{
+		"PRIVATE-TOKEN": g.token,
+	}, destination)
+	if err != nil {
+		return gitlabBackupFile{}, err
+	}
+
+	fmt.Printf("Backup saved in %s (%s, sha256 %s)\n", destination, formatBytes(size), checksum)
+	return gitlabBackupFile{
+		Path:   destination,
+		Size:   size,
+		SHA256: checksum,
+	}, nil
+}
+
+// Explain the timeout of the export or import, distinguishing it
+// from the timeout and the interruption of the command.
+func (g *gitlab) exportError(ctx context.Context, fullPath string, err error) error {
+	if err == nil || ctx.Err() != nil {
+		return err
+	}
+
+	if errors.Is(err, context.DeadlineExceeded) {
+		return fmt.Errorf("%s is not ready after %s", fullPath, g.exportTimeout())
+	}
+
+	return err
+}
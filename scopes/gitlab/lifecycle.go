@@ -0,0 +1,425 @@
+package gitlab
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"opsi/helpers"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+)
+
+// Run an operation on each project selected.
+// The pre-checks run first: the notes are only reported, the warnings
+// stop the operation on the project unless it is forced.
+func (g *gitlab) runLifecycle(ctx context.Context, options ProjectLifecycleRequest, operation gitlabLifecycleOperation) error {
+	projects, err := g.selectProjects(ctx, options.Project, options.Selector)
+	if err != nil {
+		return err
+	}
+
+	results := []gitlabLifecycleResult{}
+	for _, project := range projects {
+		// Don't touch other projects once interrupted
+		if ctx.Err() != nil {
+			break
+		}
+
+		results = append(results, g.runLifecycleProject(ctx, options, operation, project))
+	}
+
+	// Print the report
+	failed := printLifecycleResults(results)
+
+	err = helpers.Interrupted(ctx, len(results), len(projects), "projects")
+	if err != nil {
+		return err
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d projects failed", failed)
+	}
+
+	return nil
+}
+
+func (g *gitlab) runLifecycleProject(ctx context.Context, options ProjectLifecycleRequest, operation gitlabLifecycleOperation, project gitlabProjectResponse) gitlabLifecycleResult {
+	result := gitlabLifecycleResult{
+		project: project.PathWithNamespace,
+	}
+
+	// The projects already in the state requested are skipped
+	skip := operation.skip(project)
+	if skip != "" {
+		result.status = gitlabLifecycleSkipped
+		result.messages = []string{skip}
+		return result
+	}
+
+	checks, err := operation.check(ctx, project)
+	if err != nil {
+		result.status = gitlabLifecycleFailed
+		result.messages = []string{"pre-checks failed: " + err.Error()}
+		return result
+	}
+	result.messages = append(checks.notes, checks.warnings...)
+
+	if options.DryRun {
+		result.status = gitlabLifecyclePlanned
+		return result
+	}
+
+	if len(checks.warnings) > 0 && !options.Force {
+		result.status = gitlabLifecycleSkipped
+		result.messages = append(result.messages, "use --force to ignore the warnings")
+		return result
+	}
+
+	status, message, err := operation.apply(ctx, project)
+	if err != nil {
+		result.status = gitlabLifecycleFailed
+		result.messages = append(result.messages, err.Error())
+		return result
+	}
+
+	result.status = status
+	if message != "" {
+		result.messages = append(result.messages, message)
+	}
+
+	return result
+}
+
+// Print the results of the lifecycle operations
+// and return the number of failures.
+func printLifecycleResults(results []gitlabLifecycleResult) int {
+	counters := map[string]int{}
+	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(writer, "PROJECT\tRESULT\tMESSAGE")
+	for _, result := range results {
+		counters[result.status]++
+		fmt.Fprintf(writer, "%s\t%s\t%s\n", result.project, result.status, strings.Join(result.messages, "; "))
+	}
+	writer.Flush()
+
+	// Print the summary. Eg: archived: 3, skipped: 1
+	statuses := []string{}
+	for status := range counters {
+		statuses = append(statuses, status)
+	}
+	sort.Strings(statuses)
+
+	summary := []string{}
+	for _, status := range statuses {
+		summary = append(summary, fmt.Sprintf("%s: %d", status, counters[status]))
+	}
+	fmt.Printf("\n%d projects, %s\n", len(results), strings.Join(summary, ", "))
+
+	return counters[gitlabLifecycleFailed]
+}
+
+// The number of open merge requests of the project
+func (g *gitlab) countOpenMergeRequests(ctx context.Context, projectID int) (int, error) {
+	endpoint := fmt.Sprintf("/projects/%d/merge_requests", projectID)
+	response, headers, err := g.requestWithHeaders(ctx, "GET", endpoint, nil, map[string]string{
+		"state":    "opened",
+		"per_page": "1",
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	// The total is omitted on the big lists, one is enough anyway
+	total, err := strconv.Atoi(headers.Get("X-Total"))
+	if err != nil {
+		if strings.TrimSpace(string(response)) == "[]" {
+			return 0, nil
+		}
+
+		return 1, nil
+	}
+
+	return total, nil
+}
+
+func (g *gitlab) checkOpenMergeRequests(ctx context.Context, project gitlabProjectResponse, checks *gitlabLifecycleChecks) error {
+	openMergeRequests, err := g.countOpenMergeRequests(ctx, project.ID)
+	if err != nil {
+		return err
+	}
+
+	if openMergeRequests > 0 {
+		checks.warnings = append(checks.warnings, fmt.Sprintf("%d open merge requests", openMergeRequests))
+	}
+
+	return nil
+}
+
+// The namespace of the project. Eg: client-x/frontend/website -> client-x/frontend
+func projectNamespace(project gitlabProjectResponse) string {
+	index := strings.LastIndex(project.PathWithNamespace, "/")
+	if index < 0 {
+		return ""
+	}
+
+	return project.PathWithNamespace[:index]
+}
+
+// The group and all its parents. Eg: client-x/frontend -> client-x, client-x/frontend
+func groupChain(fullPath string) []string {
+	chain := []string{}
+	partials := strings.Split(strings.Trim(fullPath, "/"), "/")
+	for index := range partials {
+		chain = append(chain, strings.Join(partials[:index+1], "/"))
+	}
+
+	return chain
+}
+
+// The keys of the variables defined in the group and its parents.
+// The personal namespaces have no variables.
+func (g *gitlab) inheritedVariableKeys(ctx context.Context, fullPath string) (map[string]bool, error) {
+	keys := map[string]bool{}
+	for _, groupPath := range groupChain(fullPath) {
+		variables, err := collect[gitlabProjectListVariable](ctx, g.requestWithHeaders, "/groups/"+url.PathEscape(groupPath)+"/variables", nil)
+		if helpers.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		for _, variable := range variables {
+			keys[variable.Key] = true
+		}
+	}
+
+	return keys, nil
+}
+
+// Archive
+
+func (g *gitlab) ArchiveProjects(ctx context.Context, options ProjectLifecycleRequest) error {
+	return g.runLifecycle(ctx, options, gitlabLifecycleOperation{
+		skip: func(project gitlabProjectResponse) string {
+			if project.Archived {
+				return "already archived"
+			}
+
+			return ""
+		},
+		check: func(ctx context.Context, project gitlabProjectResponse) (gitlabLifecycleChecks, error) {
+			checks := gitlabLifecycleChecks{}
+
+			err := g.checkOpenMergeRequests(ctx, project, &checks)
+			if err != nil {
+				return checks, err
+			}
+
+			// The variables and the mirror are kept on the archived project
+			variables, err := g.listVariables(ctx, strconv.Itoa(project.ID), "all")
+			if err != nil {
+				return checks, err
+			}
+
+			if len(variables) > 0 {
+				checks.notes = append(checks.notes, fmt.Sprintf("%d CI/CD variables kept", len(variables)))
+			}
+
+			mirror, hasMirroring, err := g.checkMirroringExistence(ctx, project.ID)
+			if err != nil {
+				return checks, err
+			}
+
+			if hasMirroring {
+				checks.notes = append(checks.notes, "mirror kept on "+maskURLCredentials(mirror.Url))
+			}
+
+			return checks, nil
+		},
+		apply: func(ctx context.Context, project gitlabProjectResponse) (string, string, error) {
+			_, err := g.request(ctx, "POST", fmt.Sprintf("/projects/%d/archive", project.ID), nil, nil)
+			return gitlabLifecycleArchived, "", err
+		},
+	})
+}
+
+// Unarchive
+
+func (g *gitlab) UnarchiveProjects(ctx context.Context, options ProjectLifecycleRequest) error {
+	return g.runLifecycle(ctx, options, gitlabLifecycleOperation{
+		skip: func(project gitlabProjectResponse) string {
+			if !project.Archived {
+				return "not archived"
+			}
+
+			return ""
+		},
+		check: func(ctx context.Context, project gitlabProjectResponse) (gitlabLifecycleChecks, error) {
+			checks := gitlabLifecycleChecks{}
+
+			// The mirror is not updated while the project is archived
+			_, hasMirroring, err := g.checkMirroringExistence(ctx, project.ID)
+			if err != nil {
+				return checks, err
+			}
+
+			if !hasMirroring {
+				checks.notes = append(checks.notes, "mirroring not enabled")
+			}
+
+			return checks, nil
+		},
+		apply: func(ctx context.Context, project gitlabProjectResponse) (string, string, error) {
+			_, err := g.request(ctx, "POST", fmt.Sprintf("/projects/%d/unarchive", project.ID), nil, nil)
+			return gitlabLifecycleUnarchived, "", err
+		},
+	})
+}
+
+// Transfer
+
+func (g *gitlab) TransferProjects(ctx context.Context, options ProjectLifecycleRequest) error {
+	if options.ToGroup == "" {
+		return fmt.Errorf("missing target group")
+	}
+
+	target, err := g.resolveGroup(ctx, options.ToGroup)
+	if err != nil {
+		return err
+	}
+
+	// The variables inherited by the target group
+	targetKeys, err := g.inheritedVariableKeys(ctx, target.FullPath)
+	if err != nil {
+		return err
+	}
+
+	// The project as it will be after the transfer
+	transferred := func(project gitlabProjectResponse) gitlabProjectResponse {
+		project.PathWithNamespace = target.FullPath + "/" + project.Path
+		return project
+	}
+
+	return g.runLifecycle(ctx, options, gitlabLifecycleOperation{
+		skip: func(project gitlabProjectResponse) string {
+			if projectNamespace(project) == target.FullPath {
+				return "already in " + target.FullPath
+			}
+
+			return ""
+		},
+		check: func(ctx context.Context, project gitlabProjectResponse) (gitlabLifecycleChecks, error) {
+			checks := gitlabLifecycleChecks{}
+
+			// The path must be free in the target group
+			_, err := g.viewProject(ctx, transferred(project).PathWithNamespace)
+			if err == nil {
+				return checks, fmt.Errorf("%s already exists", transferred(project).PathWithNamespace)
+			}
+			if !helpers.IsNotFound(err) {
+				return checks, err
+			}
+
+			err = g.checkOpenMergeRequests(ctx, project, &checks)
+			if err != nil {
+				return checks, err
+			}
+
+			// The variables of the current groups are lost
+			// if not defined in the target group too.
+			sourceKeys, err := g.inheritedVariableKeys(ctx, projectNamespace(project))
+			if err != nil {
+				return checks, err
+			}
+
+			missing := []string{}
+			for key := range sourceKeys {
+				if !targetKeys[key] {
+					missing = append(missing, key)
+				}
+			}
+			sort.Strings(missing)
+
+			if len(missing) > 0 {
+				checks.warnings = append(checks.warnings, "group variables missing in the target group: "+strings.Join(missing, ", "))
+			}
+
+			mirror, hasMirroring, err := g.checkMirroringExistence(ctx, project.ID)
+			if err != nil {
+				return checks, err
+			}
+
+			if hasMirroring && g.mirrorNeedsRepointing(transferred(project), mirror) {
+				checks.notes = append(checks.notes, "mirror to be re-pointed")
+			}
+
+			return checks, nil
+		},
+		apply: func(ctx context.Context, project gitlabProjectResponse) (string, string, error) {
+			_, err := g.request(ctx, "PUT", fmt.Sprintf("/projects/%d/transfer", project.ID), nil, map[string]string{
+				"namespace": strconv.Itoa(target.ID),
+			})
+			if err != nil {
+				return gitlabLifecycleFailed, "", err
+			}
+
+			// The mirror target can depend on the group
+			message, err := g.repointMirror(ctx, transferred(project))
+			if err != nil {
+				return gitlabLifecycleFailed, "", fmt.Errorf("transferred but the mirror is not re-pointed: %s", err.Error())
+			}
+
+			return gitlabLifecycleTransferred, message, nil
+		},
+	})
+}
+
+// Check if the remote mirror points to the target configured for the project
+func (g *gitlab) mirrorNeedsRepointing(project gitlabProjectResponse, mirror gitlabMirrorResponse) bool {
+	provider, err := g.mirrorProviderFor(project)
+	if err != nil {
+		return false
+	}
+
+	pushURL := provider.pushURL(project.Path)
+	currentPath, _ := mirrorProjectPath(mirror.Url)
+	pushPath, _ := mirrorProjectPath(pushURL)
+
+	return !sameHost(mirror.Url, pushURL) || currentPath != pushPath
+}
+
+// Point the remote mirror of the project to the target configured.
+// The project must have the path and namespace updated.
+func (g *gitlab) repointMirror(ctx context.Context, project gitlabProjectResponse) (string, error) {
+	mirror, hasMirroring, err := g.checkMirroringExistence(ctx, project.ID)
+	if err != nil || !hasMirroring || !g.mirrorNeedsRepointing(project, mirror) {
+		return "", err
+	}
+
+	provider, err := g.mirrorProviderFor(project)
+	if err != nil {
+		return "", err
+	}
+
+	err = provider.setupRepository(ctx, project.Name, project.Path)
+	if err != nil {
+		return "", err
+	}
+
+	pushURL := provider.pushURL(project.Path)
+	mirror, err = g.replaceMirror(ctx, project.ID, mirror, pushURL)
+	if err != nil {
+		return "", err
+	}
+
+	err = g.syncMirror(ctx, project.ID, mirror.ID)
+	if err != nil {
+		return "", err
+	}
+
+	return "mirror re-pointed to " + maskURLCredentials(pushURL), nil
+}
@@ -0,0 +1,205 @@
+package gitlab
+
+import (
+	"context"
+	"fmt"
+	"opsi/helpers"
+	"sort"
+	"strconv"
+)
+
+func (g *gitlab) ListProjects(ctx context.Context, options ListProjectsRequest) error {
+	err := helpers.ValidateOutput(options.Output)
+	if err != nil {
+		return err
+	}
+
+	// Use the keyset pagination, faster on big instances
+	endpoint := "/projects"
+	query := map[string]string{
+		"pagination": "keyset",
+		"order_by":   "id",
+		"sort":       "asc",
+	}
+
+	// The keyset pagination is not available for the projects of a group
+	if options.Group != "" {
+		group, err := g.resolveGroup(ctx, options.Group)
+		if err != nil {
+			return err
+		}
+
+		endpoint = fmt.Sprintf("/groups/%d/projects", group.ID)
+		query = map[string]string{
+			"include_subgroups": "true",
+		}
+	}
+
+	// The filters supported by the API
+	filters := map[string]string{
+		"search":     options.Search,
+		"visibility": options.Visibility,
+		"topic":      options.Topic,
+		"archived":   options.Archived,
+	}
+	for key, value := range filters {
+		if value != "" {
+			query[key] = value
+		}
+	}
+
+	projects, err := collect[gitlabProjectResponse](ctx, g.cachedRequestWithHeaders, endpoint, query)
+	if err != nil {
+		return err
+	}
+
+	fetchMirrors := options.Mirror || options.WithMirror
+	rows := []gitlabProjectRow{}
+	for index, project := range projects {
+		if ctx.Err() != nil {
+			return helpers.Interrupted(ctx, index, len(projects), "projects")
+		}
+
+		row := gitlabProjectRow{
+			ID:            project.ID,
+			FullPath:      project.PathWithNamespace,
+			Visibility:    project.Visibility,
+			DefaultBranch: project.DefaultBranch,
+			LastActivity:  project.LastActivityAt,
+			Archived:      project.Archived,
+			SharedRunners: project.SharedRunnersEnabled,
+		}
+
+		// The remote mirrors are not provided by the list, they must be
+		// taken project by project: only when the filter or the column
+		// is requested.
+		if fetchMirrors {
+			_, hasMirroring, err := g.checkMirroringExistence(ctx, project.ID)
+			if err != nil {
+				return err
+			}
+
+			if options.Mirror && !hasMirroring {
+				continue
+			}
+
+			row.MirrorEnabled = &hasMirroring
+		}
+
+		rows = append(rows, row)
+	}
+
+	sort.Slice(rows, func(i, j int) bool {
+		return rows[i].FullPath < rows[j].FullPath
+	})
+
+	if options.Output == helpers.OutputTree {
+		nodes := []helpers.TreeNode{}
+		for _, row := range rows {
+			nodes = append(nodes, helpers.TreeNode{
+				Path:  row.FullPath,
+				Label: fmt.Sprintf("%s (#%d)", lastPathSegment(row.FullPath), row.ID),
+			})
+		}
+
+		helpers.PrintTree(nodes)
+		return nil
+	}
+
+	table := [][]string{}
+	for _, row := range rows {
+		line := []string{
+			strconv.Itoa(row.ID),
+			row.FullPath,
+			row.Visibility,
+			row.DefaultBranch,
+			row.LastActivity,
+			strconv.FormatBool(row.Archived),
+		}
+		if row.MirrorEnabled != nil {
+			line = append(line, strconv.FormatBool(*row.MirrorEnabled))
+		}
+
+		table = append(table, append(line, strconv.FormatBool(row.SharedRunners)))
+	}
+
+	headers := []string{"ID", "FULL PATH", "VISIBILITY", "DEFAULT BRANCH", "LAST ACTIVITY", "ARCHIVED"}
+	if fetchMirrors {
+		headers = append(headers, "MIRROR")
+	}
+	headers = append(headers, "SHARED RUNNERS")
+	return helpers.PrintOutput(options.Output, headers, table, rows)
+}
+
+func (g *gitlab) ListGroups(ctx context.Context, options ListGroupsRequest) error {
+	err := helpers.ValidateOutput(options.Output)
+	if err != nil {
+		return err
+	}
+
+	endpoint := "/groups"
+	query := map[string]string{}
+
+	// Take all the groups below the parent, at any level
+	if options.Parent != "" {
+		parent, err := g.resolveGroup(ctx, options.Parent)
+		if err != nil {
+			return err
+		}
+
+		endpoint = fmt.Sprintf("/groups/%d/descendant_groups", parent.ID)
+	}
+
+	if options.Search != "" {
+		query["search"] = options.Search
+	}
+
+	if options.TopLevel {
+		query["top_level_only"] = "true"
+	}
+
+	groups, err := collect[gitlabSubgroupResponse](ctx, g.cachedRequestWithHeaders, endpoint, query)
+	if err != nil {
+		return err
+	}
+
+	rows := []gitlabGroupRow{}
+	for _, group := range groups {
+		rows = append(rows, gitlabGroupRow{
+			ID:         group.ID,
+			FullPath:   group.FullPath,
+			Name:       group.Name,
+			Visibility: group.Visibility,
+		})
+	}
+
+	sort.Slice(rows, func(i, j int) bool {
+		return rows[i].FullPath < rows[j].FullPath
+	})
+
+	if options.Output == helpers.OutputTree {
+		nodes := []helpers.TreeNode{}
+		for _, row := range rows {
+			nodes = append(nodes, helpers.TreeNode{
+				Path:  row.FullPath,
+				Label: fmt.Sprintf("%s (#%d)", lastPathSegment(row.FullPath), row.ID),
+			})
+		}
+
+		helpers.PrintTree(nodes)
+		return nil
+	}
+
+	table := [][]string{}
+	for _, row := range rows {
+		table = append(table, []string{
+			strconv.Itoa(row.ID),
+			row.FullPath,
+			row.Name,
+			row.Visibility,
+		})
+	}
+
+	headers := []string{"ID", "FULL PATH", "NAME", "VISIBILITY"}
+	return helpers.PrintOutput(options.Output, headers, table, rows)
+}
@@ -0,0 +1,385 @@
+package gitlab
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"opsi/helpers"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// The gitlab instance receiving the migrated projects: the default
+// mirror or one of the mirrors targets, by group path.
+// Eg: mirror, gitlab.com/client-x
+func (g *gitlab) migrationTarget(to string) (*gitlabMirrorProvider, error) {
+	targets := map[string]GitlabMirrorOptions{
+		gitlabMigrationTargetMirror: g.mirror,
+	}
+	for _, options := range g.mirrors {
+		targets[strings.Trim(options.GroupPath, "/")] = options
+	}
+
+	options, ok := targets[strings.Trim(to, "/")]
+	if !ok {
+		return nil, fmt.Errorf("unknown migration target %s, use mirror or the group path of a mirror", to)
+	}
+
+	// Only the gitlab instances have the import API
+	if options.Provider != "" && options.Provider != gitlabMirrorProviderGitlab {
+		return nil, fmt.Errorf("the migration target %s is not a gitlab instance", to)
+	}
+
+	return &gitlabMirrorProvider{options: options}, nil
+}
+
+// The lowest role allowed by the access levels.
+// The levels of users and groups can't be moved
+// between instances, maintainer is used without roles.
+func roleAccessLevel(levels []gitlabAccessLevel) int {
+	level := -1
+	for _, accessLevel := range levels {
+		if accessLevel.UserID != nil || accessLevel.GroupID != nil {
+			continue
+		}
+
+		if level < 0 || accessLevel.AccessLevel < level {
+			level = accessLevel.AccessLevel
+		}
+	}
+
+	if level < 0 {
+		return gitlabMaintainerPermission
+	}
+
+	return level
+}
+
+// Import the export archive in the namespace provided
+func (p *gitlabMirrorProvider) importProject(ctx context.Context, project gitlabProjectResponse, namespace string, archive string) (gitlabProjectResponse, error) {
+	var imported gitlabProjectResponse
+
+	fmt.Printf("Importing %s in %s...\n", project.PathWithNamespace, namespace)
+	response, err := helpers.Upload(ctx, p.options.ApiURL+"/projects/import", map[string]string{
+		"PRIVATE-TOKEN": p.options.Token,
+	}, map[string]string{
+		"path":      project.Path,
+		"name":      project.Name,
+		"namespace": namespace,
+	}, "file", archive)
+	if err != nil {
+		return imported, err
+	}
+
+	err = json.Unmarshal(response, &imported)
+	return imported, err
+}
+
+// Wait until the import is completed.
+// The projects created from a template or an URL are imported too.
+func waitImport(ctx context.Context, request gitlabRequestFunc, project gitlabProjectResponse) error {
+	for {
+		response, _, err := request(ctx, "GET", fmt.Sprintf("/projects/%d/import", project.ID), nil, nil)
+		if err != nil {
+			return err
+		}
+
+		var status gitlabImportStatus
+		err = json.Unmarshal(response, &status)
+		if err != nil {
+			return err
+		}
+
+		if status.ImportStatus == gitlabImportFinished {
+			return nil
+		}
+
+		if status.ImportStatus == gitlabImportFailed {
+			return fmt.Errorf("the import of %s is failed: %s", project.PathWithNamespace, status.ImportError)
+		}
+
+		if !helpers.Sleep(ctx, gitlabExportPollInterval) {
+			return ctx.Err()
+		}
+	}
+}
+
+// Copy the CI/CD variables, they are not contained in the exports
+func (g *gitlab) copyVariables(ctx context.Context, projectID int, target *gitlabMirrorProvider, targetID int) (int, error) {
+	variables, err := g.listVariables(ctx, strconv.Itoa(projectID), "all")
+	if err != nil {
+		return 0, err
+	}
+
+	endpoint := fmt.Sprintf("/projects/%d/variables", targetID)
+	for _, variable := range variables {
+		_, err := target.request(ctx, "POST", endpoint, variable, nil)
+		if isAlreadyExists(err) {
+			_, err = target.request(ctx, "PUT", endpoint+"/"+url.PathEscape(variable.Key), variable, map[string]string{
+				"filter[environment_scope]": variable.EnvironmentScope,
+			})
+		}
+
+		if err != nil {
+			return 0, fmt.Errorf("variable %s (%s): %s", variable.Key, variable.EnvironmentScope, err.Error())
+		}
+	}
+
+	return len(variables), nil
+}
+
+// Protect the branches and the tags as on the source project.
+// The protections already imported are kept.
+func (g *gitlab) copyProtections(ctx context.Context, projectID int, target *gitlabMirrorProvider, targetID int) (int, error) {
+	protectedBranches, err := listProtectedBranches(ctx, g.requestWithHeaders, strconv.Itoa(projectID))
+	if err != nil {
+		return 0, err
+	}
+
+	for _, protectedBranch := range protectedBranches {
+		_, err := target.request(ctx, "POST", fmt.Sprintf("/projects/%d/protected_branches", targetID), map[string]interface{}{
+			"name":               protectedBranch.Name,
+			"push_access_level":  roleAccessLevel(protectedBranch.PushAccessLevels),
+			"merge_access_level": roleAccessLevel(protectedBranch.MergeAccessLevels),
+			"allow_force_push":   protectedBranch.AllowForcePush,
+		}, nil)
+		if err != nil && !isAlreadyExists(err) {
+			return 0, fmt.Errorf("protected branch %s: %s", protectedBranch.Name, err.Error())
+		}
+	}
+
+	protectedTags, err := collect[gitlabProtectedTagResponse](ctx, g.requestWithHeaders, fmt.Sprintf("/projects/%d/protected_tags", projectID), nil)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, protectedTag := range protectedTags {
+		_, err := target.request(ctx, "POST", fmt.Sprintf("/projects/%d/protected_tags", targetID), map[string]interface{}{
+			"name":                protectedTag.Name,
+			"create_access_level": roleAccessLevel(protectedTag.CreateAccessLevels),
+		}, nil)
+		if err != nil && !isAlreadyExists(err) {
+			return 0, fmt.Errorf("protected tag %s: %s", protectedTag.Name, err.Error())
+		}
+	}
+
+	return len(protectedBranches) + len(protectedTags), nil
+}
+
+// Compare the branches and the tags of the two projects
+// and return the differences.
+func compareRefs(ctx context.Context, source gitlabRequestFunc, sourceID string, target gitlabRequestFunc, targetID string) ([]string, error) {
+	differences := []string{}
+
+	refs := func(request gitlabRequestFunc, projectID string) (map[string]string, error) {
+		commits := map[string]string{}
+
+		branches, err := collect[gitlabBranchResponse](ctx, request, fmt.Sprintf("/projects/%s/repository/branches", projectID), nil)
+		if err != nil {
+			return nil, err
+		}
+		for _, branch := range branches {
+			commits["branch "+branch.Name] = branch.Commit.ID
+		}
+
+		tags, err := listTags(ctx, request, projectID)
+		if err != nil {
+			return nil, err
+		}
+		for _, tag := range tags {
+			commits["tag "+tag.Name] = tag.Commit.ID
+		}
+
+		return commits, nil
+	}
+
+	sourceRefs, err := refs(source, sourceID)
+	if err != nil {
+		return nil, err
+	}
+
+	targetRefs, err := refs(target, targetID)
+	if err != nil {
+		return nil, err
+	}
+
+	for ref, commit := range sourceRefs {
+		targetCommit, ok := targetRefs[ref]
+		if !ok {
+			differences = append(differences, ref+" missing")
+		} else if targetCommit != commit {
+			differences = append(differences, ref+" differs")
+		}
+	}
+
+	for ref := range targetRefs {
+		if _, ok := sourceRefs[ref]; !ok {
+			differences = append(differences, ref+" unexpected")
+		}
+	}
+
+	sort.Strings(differences)
+	return differences, nil
+}
+
+// Point the remote mirror of the source project to the migrated one,
+// so the migrated project stays updated until the source is archived.
+func (g *gitlab) repointMirrorToMigrated(ctx context.Context, project gitlabProjectResponse, target *gitlabMirrorProvider, migrated gitlabProjectResponse) (string, error) {
+	pushURL, err := rotateMirrorURL(migrated.HTTPURLToRepo, url.UserPassword(target.options.Username, target.options.Token))
+	if err != nil {
+		return "", err
+	}
+
+	mirror, hasMirroring, err := g.checkMirroringExistence(ctx, project.ID)
+	if err != nil {
+		return "", err
+	}
+
+	if hasMirroring {
+		mirror, err = g.replaceMirror(ctx, project.ID, mirror, pushURL)
+	} else {
+		mirror, err = g.createMirror(ctx, project.ID, pushURL)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	return maskURLCredentials(pushURL), g.syncMirror(ctx, project.ID, mirror.ID)
+}
+
+func (g *gitlab) MigrateProject(ctx context.Context, options MigrateRequest) error {
+	if options.Group == "" {
+		return errors.New("missing target group")
+	}
+
+	target, err := g.migrationTarget(options.To)
+	if err != nil {
+		return err
+	}
+
+	err = target.verifyToken(ctx)
+	if err != nil {
+		return err
+	}
+
+	project, err := g.resolveProject(ctx, options.Project)
+	if err != nil {
+		return err
+	}
+
+	// The target group must exist and the path must be free
+	response, err := target.request(ctx, "GET", "/groups/"+encodeReference(options.Group), nil, nil)
+	if helpers.IsNotFound(err) {
+		return fmt.Errorf("the group %s is not found on %s", options.Group, target.options.ApiURL)
+	}
+	if err != nil {
+		return err
+	}
+
+	var targetGroup gitlabSubgroupResponse
+	err = json.Unmarshal(response, &targetGroup)
+	if err != nil {
+		return err
+	}
+
+	targetPath := targetGroup.FullPath + "/" + project.Path
+	exists, err := target.repositoryExists(ctx, targetPath)
+	if err != nil {
+		return err
+	}
+
+	if exists {
+		return fmt.Errorf("the project %s already exists on %s", targetPath, target.options.ApiURL)
+	}
+
+	// Export and import. The export is not a backup: it is kept
+	// only to retry by hand when the import is not completed.
+	directory, err := os.MkdirTemp("", "opsi-migration-")
+	if err != nil {
+		return err
+	}
+
+	archive, err := g.exportProject(ctx, project, directory)
+	if err != nil {
+		os.RemoveAll(directory)
+		return err
+	}
+
+	migrated, err := target.importProject(ctx, project, targetGroup.FullPath, archive.Path)
+	if err != nil {
+		return fmt.Errorf("%s, the export is kept in %s", err.Error(), archive.Path)
+	}
+
+	waitCtx, cancel := context.WithTimeout(ctx, g.exportTimeout())
+	defer cancel()
+
+	err = waitImport(waitCtx, target.requestWithHeaders, migrated)
+	if err != nil {
+		return fmt.Errorf("%s, the export is kept in %s", g.exportError(ctx, migrated.PathWithNamespace, err).Error(), archive.Path)
+	}
+	fmt.Printf("Project imported as %s (#%d)\n", migrated.PathWithNamespace, migrated.ID)
+
+	err = os.RemoveAll(directory)
+	if err != nil {
+		fmt.Printf("The export %s is not removed: %s\n", archive.Path, err.Error())
+	}
+
+	// Recreate what is not contained in the export.
+	// Continue on failures, so the report is complete.
+	failures := []string{}
+
+	variables, err := g.copyVariables(ctx, project.ID, target, migrated.ID)
+	if err != nil {
+		failures = append(failures, "variables: "+err.Error())
+	} else {
+		fmt.Printf("%d CI/CD variables copied\n", variables)
+	}
+
+	protections, err := g.copyProtections(ctx, project.ID, target, migrated.ID)
+	if err != nil {
+		failures = append(failures, "protections: "+err.Error())
+	} else {
+		fmt.Printf("%d protected branches and tags copied\n", protections)
+	}
+
+	if project.ContainerExpirationPolicy != nil {
+		_, err = target.request(ctx, "PUT", fmt.Sprintf("/projects/%d", migrated.ID), map[string]interface{}{
+			"container_expiration_policy_attributes": project.ContainerExpirationPolicy,
+		}, nil)
+		if err != nil {
+			failures = append(failures, "cleanup policy: "+err.Error())
+		} else {
+			fmt.Println("Cleanup policy copied")
+		}
+	}
+
+	// Verify the repository
+	differences, err := compareRefs(ctx, g.requestWithHeaders, strconv.Itoa(project.ID), target.requestWithHeaders, strconv.Itoa(migrated.ID))
+	if err != nil {
+		failures = append(failures, "refs verification: "+err.Error())
+	} else if len(differences) > 0 {
+		failures = append(failures, "refs verification: "+strings.Join(differences, ", "))
+	} else {
+		fmt.Println("Branches and tags verified")
+	}
+
+	if options.RepointMirror {
+		mirrorURL, err := g.repointMirrorToMigrated(ctx, project, target, migrated)
+		if err != nil {
+			failures = append(failures, "mirror: "+err.Error())
+		} else {
+			fmt.Printf("The mirror of %s now points to %s\n", project.PathWithNamespace, mirrorURL)
+		}
+	}
+
+	if len(failures) > 0 {
+		return fmt.Errorf("the project is migrated with errors:\n- %s", strings.Join(failures, "\n- "))
+	}
+
+	fmt.Printf("The project %s is migrated to %s\n", project.PathWithNamespace, migrated.PathWithNamespace)
+	return nil
+}
@@ -0,0 +1,28 @@
+package gitlab
+
+import "testing"
+
+func TestRoleAccessLevel(t *testing.T) {
+	userID := 7
+	groupID := 9
+
+	tests := []struct {
+		name   string
+		levels []gitlabAccessLevel
+		level  int
+	}{
+		{"no levels", []gitlabAccessLevel{}, gitlabMaintainerPermission},
+		{"developers", []gitlabAccessLevel{{AccessLevel: gitlabDeveloperPermission}}, gitlabDeveloperPermission},
+		{"no one", []gitlabAccessLevel{{AccessLevel: 0}}, 0},
+		{"lowest role", []gitlabAccessLevel{{AccessLevel: gitlabMaintainerPermission}, {AccessLevel: gitlabDeveloperPermission}}, gitlabDeveloperPermission},
+		{"only user and group", []gitlabAccessLevel{{AccessLevel: gitlabDeveloperPermission, UserID: &userID}, {AccessLevel: gitlabDeveloperPermission, GroupID: &groupID}}, gitlabMaintainerPermission},
+		{"role with user", []gitlabAccessLevel{{AccessLevel: gitlabDeveloperPermission, UserID: &userID}, {AccessLevel: gitlabMaintainerPermission}}, gitlabMaintainerPermission},
+	}
+
+	for _, test := range tests {
+		level := roleAccessLevel(test.levels)
+		if level != test.level {
+			t.Errorf("%s: roleAccessLevel = %d; want %d", test.name, level, test.level)
+		}
+	}
+}
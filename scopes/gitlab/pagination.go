@@ -0,0 +1,100 @@
+package gitlab
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+const gitlabPageSize string = "100"
+
+// Walk through all the pages of a list endpoint.
+// Each item is decoded and passed to the callback as soon as its page
+// is received. The iteration stops when the callback returns false.
+//
+// The next page is taken from the Link header (offset and keyset
+// pagination) or from the X-Next-Page header, so nothing is truncated.
+func walk[T any](ctx context.Context, request gitlabRequestFunc, endpoint string, query map[string]string, callback func(T) bool) error {
+	params := map[string]string{
+		"per_page": gitlabPageSize,
+	}
+	for key, value := range query {
+		params[key] = value
+	}
+
+	for {
+		response, headers, err := request(ctx, "GET", endpoint, nil, params)
+		if err != nil {
+			return err
+		}
+
+		var items []T
+		err = json.Unmarshal(response, &items)
+		if err != nil {
+			return err
+		}
+
+		for _, item := range items {
+			if !callback(item) {
+				return nil
+			}
+		}
+
+		next, ok := nextPageParams(headers)
+		if !ok || len(items) == 0 {
+			return nil
+		}
+
+		for key, value := range next {
+			params[key] = value
+		}
+	}
+}
+
+// Collect all the items of a list endpoint.
+func collect[T any](ctx context.Context, request gitlabRequestFunc, endpoint string, query map[string]string) ([]T, error) {
+	items := []T{}
+	err := walk(ctx, request, endpoint, query, func(item T) bool {
+		items = append(items, item)
+		return true
+	})
+
+	return items, err
+}
+
+// Take the query params of the next page from the response headers.
+func nextPageParams(headers http.Header) (map[string]string, bool) {
+	// The Link header contains the full URL of the next page.
+	// Eg: <https://gitlab.com/api/v4/projects?id_after=42&per_page=100>; rel="next"
+	for _, link := range strings.Split(headers.Get("Link"), ",") {
+		if !strings.Contains(link, `rel="next"`) {
+			continue
+		}
+
+		start := strings.Index(link, "<")
+		end := strings.Index(link, ">")
+		if start < 0 || end < start {
+			continue
+		}
+
+		nextURL, err := url.Parse(link[start+1 : end])
+		if err != nil {
+			continue
+		}
+
+		params := map[string]string{}
+		for key, values := range nextURL.Query() {
+			params[key] = values[0]
+		}
+
+		return params, true
+	}
+
+	if page := headers.Get("X-Next-Page"); page != "" {
+		return map[string]string{"page": page}, true
+	}
+
+	return nil, false
+}
@@ -0,0 +1,66 @@
+package gitlab
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestNextPageParams(t *testing.T) {
+	tests := []struct {
+		name    string
+		headers map[string]string
+		params  map[string]string
+		ok      bool
+	}{
+		{
+			name: "keyset link",
+			headers: map[string]string{
+				"Link": `<https://gitlab.com/api/v4/projects?id_after=42&per_page=100>; rel="next"`,
+			},
+			params: map[string]string{"id_after": "42", "per_page": "100"},
+			ok:     true,
+		},
+		{
+			name: "next link among others",
+			headers: map[string]string{
+				"Link": `<https://gitlab.com/api/v4/groups?page=1>; rel="first", <https://gitlab.com/api/v4/groups?page=3>; rel="next", <https://gitlab.com/api/v4/groups?page=9>; rel="last"`,
+			},
+			params: map[string]string{"page": "3"},
+			ok:     true,
+		},
+		{
+			name: "offset header",
+			headers: map[string]string{
+				"X-Next-Page": "2",
+			},
+			params: map[string]string{"page": "2"},
+			ok:     true,
+		},
+		{
+			name: "last page",
+			headers: map[string]string{
+				"Link":        `<https://gitlab.com/api/v4/groups?page=1>; rel="first"`,
+				"X-Next-Page": "",
+			},
+			ok: false,
+		},
+		{
+			name:    "no headers",
+			headers: map[string]string{},
+			ok:      false,
+		},
+	}
+
+	for _, test := range tests {
+		headers := http.Header{}
+		for key, value := range test.headers {
+			headers.Set(key, value)
+		}
+
+		params, ok := nextPageParams(headers)
+		if ok != test.ok || (test.ok && !reflect.DeepEqual(params, test.params)) {
+			t.Errorf("%s: nextPageParams = %v, %v; want %v, %v", test.name, params, ok, test.params, test.ok)
+		}
+	}
+}
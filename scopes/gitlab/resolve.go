@@ -0,0 +1,276 @@
+package gitlab
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"opsi/helpers"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const gitlabMaxSuggestions = 3
+
+// Check if the reference is a numeric ID
+func isNumericID(ref string) bool {
+	_, err := strconv.Atoi(ref)
+	return err == nil
+}
+
+// Clean the reference provided by the user.
+// The web URLs are accepted too.
+// Eg: https://gitlab.com/client-x/website/ -> client-x/website
+func normalizeReference(ref string) string {
+	ref = strings.TrimSpace(ref)
+
+	if strings.Contains(ref, "://") {
+		parsedURL, err := url.Parse(ref)
+		if err == nil {
+			ref = parsedURL.Path
+		}
+
+		// Remove the suffix of the web pages. Eg: /-/settings/ci_cd
+		if index := strings.Index(ref, "/-/"); index >= 0 {
+			ref = ref[:index]
+		}
+	}
+
+	return strings.TrimSuffix(strings.Trim(ref, "/"), ".git")
+}
+
+// Encode the reference for the API.
+// The IDs are used as they are, the paths are URL-encoded.
+// Eg: client-x/website -> client-x%2Fwebsite
+func encodeReference(ref string) string {
+	ref = normalizeReference(ref)
+	if isNumericID(ref) {
+		return ref
+	}
+
+	return url.PathEscape(ref)
+}
+
+// The distance between two strings
+// as number of edits needed to transform one in the other.
+func levenshtein(first string, second string) int {
+	a := []rune(first)
+	b := []rune(second)
+
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+
+			current[j] = previous[j] + 1
+			if current[j-1]+1 < current[j] {
+				current[j] = current[j-1] + 1
+			}
+			if previous[j-1]+cost < current[j] {
+				current[j] = previous[j-1] + cost
+			}
+		}
+
+		previous, current = current, previous
+	}
+
+	return previous[len(b)]
+}
+
+// Take the candidates closest to the reference.
+// The candidates too different from the reference are discarded.
+func closestReferences(ref string, candidates []string) []string {
+	ref = strings.ToLower(ref)
+	threshold := len(ref) / 2
+	if threshold < 3 {
+		threshold = 3
+	}
+
+	distances := map[string]int{}
+	for _, candidate := range candidates {
+		distance := levenshtein(ref, strings.ToLower(candidate))
+
+		// A candidate containing the reference is always a good suggestion
+		if strings.Contains(strings.ToLower(candidate), ref) {
+			distance = 0
+		}
+
+		if distance <= threshold {
+			distances[candidate] = distance
+		}
+	}
+
+	closest := []string{}
+	for candidate := range distances {
+		closest = append(closest, candidate)
+	}
+
+	sort.Slice(closest, func(i, j int) bool {
+		if distances[closest[i]] == distances[closest[j]] {
+			return closest[i] < closest[j]
+		}
+
+		return distances[closest[i]] < distances[closest[j]]
+	})
+
+	if len(closest) > gitlabMaxSuggestions {
+		closest = closest[:gitlabMaxSuggestions]
+	}
+
+	return closest
+}
+
+func notFoundError(kind string, ref string, suggestions []string) error {
+	if len(suggestions) == 0 {
+		return fmt.Errorf("%s %s not found", kind, ref)
+	}
+
+	return fmt.Errorf("%s %s not found, did you mean %s?", kind, ref, strings.Join(suggestions, ", "))
+}
+
+// Take the last part of the path, the most meaningful
+// to search the suggestions. Eg: client-x/backend/api -> api
+func lastPathSegment(path string) string {
+	partials := strings.Split(path, "/")
+	return partials[len(partials)-1]
+}
+
+// Search the candidates for the suggestions. Only the first page is
+// taken, the search API is fuzzy and the best matches come first.
+func (g *gitlab) searchReferences(ctx context.Context, endpoint string, term string, decode func([]byte) ([]string, error)) []string {
+	response, err := g.cachedRequest(ctx, "GET", endpoint, nil, map[string]string{
+		"search":   term,
+		"per_page": gitlabPageSize,
+	})
+	if err != nil {
+		return nil
+	}
+
+	candidates, err := decode(response)
+	if err != nil {
+		return nil
+	}
+
+	return candidates
+}
+
+// Find a project by ID, by full path or by web URL.
+// Eg: 1234, client-x/website, https://gitlab.com/client-x/website
+func (g *gitlab) resolveProject(ctx context.Context, ref string) (gitlabProjectResponse, error) {
+	ref = normalizeReference(ref)
+	if ref == "" {
+		return gitlabProjectResponse{}, fmt.Errorf("missing project reference")
+	}
+
+	project, err := g.viewProject(ctx, ref)
+	if !helpers.IsNotFound(err) {
+		return project, err
+	}
+
+	if isNumericID(ref) {
+		return project, notFoundError("project", ref, nil)
+	}
+
+	candidates := g.searchReferences(ctx, "/projects", lastPathSegment(ref), func(response []byte) ([]string, error) {
+		var projects []gitlabProjectResponse
+		err := json.Unmarshal(response, &projects)
+
+		paths := []string{}
+		for _, project := range projects {
+			paths = append(paths, project.PathWithNamespace)
+		}
+
+		return paths, err
+	})
+
+	return project, notFoundError("project", ref, closestReferences(ref, candidates))
+}
+
+// Find a group by ID, by full path or by web URL.
+// Eg: 1234, client-x, client-x/frontend
+func (g *gitlab) resolveGroup(ctx context.Context, ref string) (gitlabSubgroupResponse, error) {
+	ref = normalizeReference(ref)
+	if ref == "" {
+		return gitlabSubgroupResponse{}, fmt.Errorf("missing group reference")
+	}
+
+	group, err := g.viewGroup(ctx, ref)
+	if !helpers.IsNotFound(err) {
+		return group, err
+	}
+
+	if isNumericID(ref) {
+		return group, notFoundError("group", ref, nil)
+	}
+
+	candidates := g.searchReferences(ctx, "/groups", lastPathSegment(ref), func(response []byte) ([]string, error) {
+		var groups []gitlabSubgroupResponse
+		err := json.Unmarshal(response, &groups)
+
+		paths := []string{}
+		for _, group := range groups {
+			paths = append(paths, group.FullPath)
+		}
+
+		return paths, err
+	})
+
+	return group, notFoundError("group", ref, closestReferences(ref, candidates))
+}
+
+// Find a user by ID or by username.
+func (g *gitlab) resolveUser(ctx context.Context, ref string) (gitlabUser, error) {
+	var user gitlabUser
+
+	ref = strings.TrimPrefix(normalizeReference(ref), "@")
+	if ref == "" {
+		return user, fmt.Errorf("missing user reference")
+	}
+
+	if isNumericID(ref) {
+		response, err := g.cachedRequest(ctx, "GET", "/users/"+ref, nil, nil)
+		if helpers.IsNotFound(err) {
+			return user, notFoundError("user", ref, nil)
+		} else if err != nil {
+			return user, err
+		}
+
+		err = json.Unmarshal(response, &user)
+		return user, err
+	}
+
+	users, err := g.listUsers(ctx, map[string]string{
+		"username": ref,
+	})
+	if err != nil {
+		return user, err
+	}
+
+	if len(users) > 0 {
+		return users[0], nil
+	}
+
+	candidates := g.searchReferences(ctx, "/users", ref, func(response []byte) ([]string, error) {
+		var users []gitlabUser
+		err := json.Unmarshal(response, &users)
+
+		usernames := []string{}
+		for _, user := range users {
+			usernames = append(usernames, user.Username)
+		}
+
+		return usernames, err
+	})
+
+	return user, notFoundError("user", ref, closestReferences(ref, candidates))
+}
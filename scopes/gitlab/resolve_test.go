@@ -0,0 +1,86 @@
+package gitlab
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNormalizeReference(t *testing.T) {
+	tests := []struct {
+		ref        string
+		normalized string
+	}{
+		{"1234", "1234"},
+		{" client-x/website ", "client-x/website"},
+		{"/client-x/website/", "client-x/website"},
+		{"https://gitlab.com/client-x/website", "client-x/website"},
+		{"https://gitlab.com/client-x/website/-/settings/ci_cd", "client-x/website"},
+		{"https://gitlab.com/client-x/website.git", "client-x/website"},
+	}
+
+	for _, test := range tests {
+		normalized := normalizeReference(test.ref)
+		if normalized != test.normalized {
+			t.Errorf("normalizeReference(%q) = %q; want %q", test.ref, normalized, test.normalized)
+		}
+	}
+}
+
+func TestEncodeReference(t *testing.T) {
+	tests := []struct {
+		ref     string
+		encoded string
+	}{
+		{"1234", "1234"},
+		{"client-x/website", "client-x%2Fwebsite"},
+		{"https://gitlab.com/client-x/frontend/website", "client-x%2Ffrontend%2Fwebsite"},
+	}
+
+	for _, test := range tests {
+		encoded := encodeReference(test.ref)
+		if encoded != test.encoded {
+			t.Errorf("encodeReference(%q) = %q; want %q", test.ref, encoded, test.encoded)
+		}
+	}
+}
+
+func TestClosestReferences(t *testing.T) {
+	tests := []struct {
+		name       string
+		ref        string
+		candidates []string
+		closest    []string
+	}{
+		{
+			name:       "typo",
+			ref:        "client-x/websit",
+			candidates: []string{"other/project", "client-y/website", "client-x/website"},
+			closest:    []string{"client-x/website", "client-y/website"},
+		},
+		{
+			name:       "case insensitive",
+			ref:        "Client-X",
+			candidates: []string{"client-x/website"},
+			closest:    []string{"client-x/website"},
+		},
+		{
+			name:       "limited suggestions",
+			ref:        "web",
+			candidates: []string{"d/web", "c/web", "b/web", "a/web"},
+			closest:    []string{"a/web", "b/web", "c/web"},
+		},
+		{
+			name:       "too different",
+			ref:        "website",
+			candidates: []string{"infrastructure/terraform"},
+			closest:    []string{},
+		},
+	}
+
+	for _, test := range tests {
+		closest := closestReferences(test.ref, test.candidates)
+		if !reflect.DeepEqual(closest, test.closest) {
+			t.Errorf("%s: closestReferences(%q) = %v; want %v", test.name, test.ref, closest, test.closest)
+		}
+	}
+}
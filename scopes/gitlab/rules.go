@@ -0,0 +1,247 @@
+package gitlab
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"opsi/helpers"
+	"sort"
+	"strings"
+)
+
+// The paid features answer forbidden or not found on the other tiers
+func tierUnavailable(feature string, err error) error {
+	status := helpers.StatusCode(err)
+	if status == http.StatusForbidden || status == http.StatusNotFound {
+		return fmt.Errorf("%s %w", feature, errTierUnavailable)
+	}
+
+	return err
+}
+
+// Align the push rules of the project with the ones provided.
+// Nothing is done when no push rule is provided.
+func (g *gitlab) applyPushRules(ctx context.Context, project gitlabProjectResponse) ([]string, error) {
+	if g.pushRules == (GitlabPushRulesOptions{}) {
+		return nil, nil
+	}
+
+	endpoint := fmt.Sprintf("/projects/%d/push_rule", project.ID)
+
+	// The push rule is null until created
+	var current *GitlabPushRulesOptions
+	response, err := g.request(ctx, "GET", endpoint, nil, nil)
+	if err != nil && !helpers.IsNotFound(err) {
+		return nil, tierUnavailable("push rules", err)
+	}
+
+	if err == nil {
+		err = json.Unmarshal(response, &current)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if current != nil && *current == g.pushRules {
+		return nil, nil
+	}
+
+	method := "POST"
+	if current != nil {
+		method = "PUT"
+	}
+
+	_, err = g.request(ctx, method, endpoint, g.pushRules, nil)
+	if err != nil {
+		return nil, tierUnavailable("push rules", err)
+	}
+
+	return []string{"push rules updated"}, nil
+}
+
+// The approval rules of the project
+func (g *gitlab) projectApprovalRules(project gitlabProjectResponse) []GitlabApprovalRuleOptions {
+	rules := []GitlabApprovalRuleOptions{}
+	for _, rule := range g.approvalRules {
+		matches := len(rule.Groups) == 0
+		for _, glob := range rule.Groups {
+			matches = matches || matchesNamespaceGlob(glob, project)
+		}
+
+		if matches {
+			rules = append(rules, rule)
+		}
+	}
+
+	return rules
+}
+
+// The payload of the approval rule, with the users, the groups
+// and the protected branches resolved to their IDs
+func (g *gitlab) approvalRulePayload(ctx context.Context, rule GitlabApprovalRuleOptions, protectedBranches []gitlabProtectedBranchResponse) (gitlabApprovalRuleRequest, error) {
+	payload := gitlabApprovalRuleRequest{
+		Name:               rule.Name,
+		ApprovalsRequired:  rule.ApprovalsRequired,
+		UserIDs:            []int{},
+		GroupIDs:           []int{},
+		ProtectedBranchIDs: []int{},
+	}
+
+	for _, username := range rule.Users {
+		user, err := g.resolveUser(ctx, username)
+		if err != nil {
+			return payload, err
+		}
+
+		payload.UserIDs = append(payload.UserIDs, user.ID)
+	}
+
+	for _, groupPath := range rule.ApproverGroups {
+		group, err := g.resolveGroup(ctx, groupPath)
+		if err != nil {
+			return payload, err
+		}
+
+		payload.GroupIDs = append(payload.GroupIDs, group.ID)
+	}
+
+	for _, name := range rule.ProtectedBranches {
+		found := false
+		for _, branch := range protectedBranches {
+			if branch.Name == name {
+				payload.ProtectedBranchIDs = append(payload.ProtectedBranchIDs, branch.ID)
+				found = true
+			}
+		}
+
+		if !found {
+			return payload, fmt.Errorf("the branch %s of the approval rule %s is not protected", name, rule.Name)
+		}
+	}
+
+	sort.Ints(payload.UserIDs)
+	sort.Ints(payload.GroupIDs)
+	sort.Ints(payload.ProtectedBranchIDs)
+
+	return payload, nil
+}
+
+func entitiesIDs(entities []gitlabEntityWithID) []int {
+	ids := []int{}
+	for _, entity := range entities {
+		ids = append(ids, entity.ID)
+	}
+	sort.Ints(ids)
+
+	return ids
+}
+
+func sameIDs(a []int, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for index := range a {
+		if a[index] != b[index] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Check if the approval rule has the settings of the payload
+func approvalRuleMatches(rule gitlabApprovalRuleResponse, payload gitlabApprovalRuleRequest) bool {
+	return rule.ApprovalsRequired == payload.ApprovalsRequired &&
+		sameIDs(entitiesIDs(rule.Users), payload.UserIDs) &&
+		sameIDs(entitiesIDs(rule.Groups), payload.GroupIDs) &&
+		sameIDs(entitiesIDs(rule.ProtectedBranches), payload.ProtectedBranchIDs)
+}
+
+// Align the merge request approval rules of the project with the ones provided.
+// The rules are matched by name, the other rules of the project are untouched.
+func (g *gitlab) applyApprovalRules(ctx context.Context, project gitlabProjectResponse) ([]string, error) {
+	rules := g.projectApprovalRules(project)
+	if len(rules) == 0 {
+		return nil, nil
+	}
+
+	endpoint := fmt.Sprintf("/projects/%d/approval_rules", project.ID)
+	changes := []string{}
+
+	current, err := collect[gitlabApprovalRuleResponse](ctx, g.requestWithHeaders, endpoint, nil)
+	if err != nil {
+		return changes, tierUnavailable("merge request approval rules", err)
+	}
+
+	protectedBranches, err := collect[gitlabProtectedBranchResponse](ctx, g.requestWithHeaders, fmt.Sprintf("/projects/%d/protected_branches", project.ID), nil)
+	if err != nil {
+		return changes, err
+	}
+
+	for _, rule := range rules {
+		payload, err := g.approvalRulePayload(ctx, rule, protectedBranches)
+		if err != nil {
+			return changes, err
+		}
+
+		var existing *gitlabApprovalRuleResponse
+		for index, currentRule := range current {
+			if currentRule.RuleType == gitlabApprovalRuleRegular && strings.EqualFold(currentRule.Name, rule.Name) {
+				existing = &current[index]
+				break
+			}
+		}
+
+		if existing == nil {
+			_, err = g.request(ctx, "POST", endpoint, payload, nil)
+			if err != nil {
+				return changes, tierUnavailable("merge request approval rules", err)
+			}
+
+			changes = append(changes, "approval rule "+rule.Name+" created")
+			continue
+		}
+
+		if approvalRuleMatches(*existing, payload) {
+			continue
+		}
+
+		_, err = g.request(ctx, "PUT", fmt.Sprintf("%s/%d", endpoint, existing.ID), payload, nil)
+		if err != nil {
+			return changes, tierUnavailable("merge request approval rules", err)
+		}
+
+		changes = append(changes, "approval rule "+rule.Name+" updated")
+	}
+
+	return changes, nil
+}
+
+// Apply the push rules and the approval rules to the project.
+// The rules not available on the tier of the instance are reported
+// without failing, the project is usable anyway.
+func (g *gitlab) applyProjectRules(ctx context.Context, project gitlabProjectResponse) ([]string, error) {
+	changes := []string{}
+	appliers := []func(context.Context, gitlabProjectResponse) ([]string, error){
+		g.applyPushRules,
+		g.applyApprovalRules,
+	}
+
+	for _, apply := range appliers {
+		applied, err := apply(ctx, project)
+		changes = append(changes, applied...)
+
+		if errors.Is(err, errTierUnavailable) {
+			changes = append(changes, err.Error()+", skipped")
+			continue
+		}
+		if err != nil {
+			return changes, err
+		}
+	}
+
+	return changes, nil
+}
@@ -0,0 +1,65 @@
+package gitlab
+
+import (
+	"errors"
+	"net/http"
+	"opsi/helpers"
+	"testing"
+)
+
+func TestApprovalRuleMatches(t *testing.T) {
+	payload := gitlabApprovalRuleRequest{
+		Name:               "maintainers",
+		ApprovalsRequired:  2,
+		UserIDs:            []int{3, 5},
+		GroupIDs:           []int{8},
+		ProtectedBranchIDs: []int{},
+	}
+
+	entities := func(ids ...int) []gitlabEntityWithID {
+		result := []gitlabEntityWithID{}
+		for _, id := range ids {
+			result = append(result, gitlabEntityWithID{ID: id})
+		}
+		return result
+	}
+
+	tests := []struct {
+		name    string
+		rule    gitlabApprovalRuleResponse
+		matches bool
+	}{
+		{"same", gitlabApprovalRuleResponse{ApprovalsRequired: 2, Users: entities(5, 3), Groups: entities(8)}, true},
+		{"approvals", gitlabApprovalRuleResponse{ApprovalsRequired: 1, Users: entities(3, 5), Groups: entities(8)}, false},
+		{"user missing", gitlabApprovalRuleResponse{ApprovalsRequired: 2, Users: entities(3), Groups: entities(8)}, false},
+		{"user added", gitlabApprovalRuleResponse{ApprovalsRequired: 2, Users: entities(3, 5, 9), Groups: entities(8)}, false},
+		{"other group", gitlabApprovalRuleResponse{ApprovalsRequired: 2, Users: entities(3, 5), Groups: entities(9)}, false},
+		{"branch scoped", gitlabApprovalRuleResponse{ApprovalsRequired: 2, Users: entities(3, 5), Groups: entities(8), ProtectedBranches: entities(1)}, false},
+	}
+
+	for _, test := range tests {
+		matches := approvalRuleMatches(test.rule, payload)
+		if matches != test.matches {
+			t.Errorf("%s: approvalRuleMatches = %v; want %v", test.name, matches, test.matches)
+		}
+	}
+}
+
+func TestTierUnavailable(t *testing.T) {
+	tests := []struct {
+		statusCode  int
+		unavailable bool
+	}{
+		{http.StatusForbidden, true},
+		{http.StatusNotFound, true},
+		{http.StatusBadRequest, false},
+		{http.StatusInternalServerError, false},
+	}
+
+	for _, test := range tests {
+		err := tierUnavailable("push rules", &helpers.APIError{StatusCode: test.statusCode})
+		if errors.Is(err, errTierUnavailable) != test.unavailable {
+			t.Errorf("tierUnavailable(%d) = %v; want unavailable %v", test.statusCode, err, test.unavailable)
+		}
+	}
+}
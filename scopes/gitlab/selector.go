@@ -0,0 +1,112 @@
+package gitlab
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// The keys allowed in the project selectors
+var gitlabSelectorKeys = []string{"group", "topic", "search"}
+
+// Parse a selector of projects.
+// Eg: group=client-x,topic=release -> {group: client-x, topic: release}
+func parseSelector(selector string) (map[string]string, error) {
+	filters := map[string]string{}
+
+	for _, part := range strings.Split(selector, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		key, value, found := strings.Cut(part, "=")
+		key = strings.TrimSpace(key)
+		value = strings.TrimSpace(value)
+		if !found || value == "" {
+			return nil, fmt.Errorf("invalid selector %s, use key=value", part)
+		}
+
+		allowed := false
+		for _, selectorKey := range gitlabSelectorKeys {
+			allowed = allowed || selectorKey == key
+		}
+
+		if !allowed {
+			return nil, fmt.Errorf("invalid selector key %s, allowed keys are %s", key, strings.Join(gitlabSelectorKeys, ", "))
+		}
+
+		filters[key] = value
+	}
+
+	// Never select all the projects of the instance
+	if len(filters) == 0 {
+		return nil, errors.New("the selector is empty")
+	}
+
+	return filters, nil
+}
+
+// Take the projects interested by a command.
+// The project reference and the selector are alternatives.
+func (g *gitlab) selectProjects(ctx context.Context, projectRef string, selector string) ([]gitlabProjectResponse, error) {
+	if projectRef != "" && selector != "" {
+		return nil, errors.New("provide a project or a selector, not both")
+	}
+
+	if projectRef == "" && selector == "" {
+		return nil, errors.New("provide a project or a selector")
+	}
+
+	if projectRef != "" {
+		project, err := g.resolveProject(ctx, projectRef)
+		if err != nil {
+			return nil, err
+		}
+
+		return []gitlabProjectResponse{project}, nil
+	}
+
+	filters, err := parseSelector(selector)
+	if err != nil {
+		return nil, err
+	}
+
+	endpoint := "/projects"
+	query := map[string]string{
+		"pagination": "keyset",
+		"order_by":   "id",
+		"sort":       "asc",
+	}
+
+	// The keyset pagination is not available for the projects of a group
+	if filters["group"] != "" {
+		group, err := g.resolveGroup(ctx, filters["group"])
+		if err != nil {
+			return nil, err
+		}
+
+		endpoint = fmt.Sprintf("/groups/%d/projects", group.ID)
+		query = map[string]string{
+			"include_subgroups": "true",
+		}
+	}
+
+	for _, key := range []string{"topic", "search"} {
+		if filters[key] != "" {
+			query[key] = filters[key]
+		}
+	}
+
+	projects, err := collect[gitlabProjectResponse](ctx, g.cachedRequestWithHeaders, endpoint, query)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(projects) == 0 {
+		return nil, fmt.Errorf("no projects matching the selector %s", selector)
+	}
+
+	return projects, nil
+}
@@ -0,0 +1,43 @@
+package gitlab
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseSelector(t *testing.T) {
+	tests := []struct {
+		selector string
+		filters  map[string]string
+		fails    bool
+	}{
+		{"group=client-x", map[string]string{"group": "client-x"}, false},
+		{"group=client-x,topic=release", map[string]string{"group": "client-x", "topic": "release"}, false},
+		{" group = client-x , search=web ,", map[string]string{"group": "client-x", "search": "web"}, false},
+		{"search=a=b", map[string]string{"search": "a=b"}, false},
+		{"", nil, true},
+		{",", nil, true},
+		{"group", nil, true},
+		{"group=", nil, true},
+		{"visibility=private", nil, true},
+	}
+
+	for _, test := range tests {
+		filters, err := parseSelector(test.selector)
+		if test.fails {
+			if err == nil {
+				t.Errorf("parseSelector(%q) = %v; want an error", test.selector, filters)
+			}
+			continue
+		}
+
+		if err != nil {
+			t.Errorf("parseSelector(%q) failed: %s", test.selector, err)
+			continue
+		}
+
+		if !reflect.DeepEqual(filters, test.filters) {
+			t.Errorf("parseSelector(%q) = %v; want %v", test.selector, filters, test.filters)
+		}
+	}
+}
@@ -0,0 +1,273 @@
+package gitlab
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"opsi/helpers"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+)
+
+// Create the project with its initial content: a README, the content of
+// a template project, an external repository or a local skeleton directory.
+// The project returned has always a repository with a commit.
+func (g *gitlab) createProjectFrom(ctx context.Context, options ProjectRequest, namespaceID int) (gitlabProjectResponse, error) {
+	sources := 0
+	for _, source := range []string{options.FromTemplate, options.ImportURL, options.Skeleton} {
+		if source != "" {
+			sources++
+		}
+	}
+
+	if sources > 1 {
+		return gitlabProjectResponse{}, errors.New("provide only one of template, import URL and skeleton")
+	}
+
+	payload := projectPayload(options, namespaceID)
+
+	switch {
+	case options.FromTemplate != "":
+		return g.createProjectFromTemplate(ctx, options, payload)
+	case options.ImportURL != "":
+		payload.InitializeWithReadME = false
+		payload.ImportURL = options.ImportURL
+
+		fmt.Printf("Importing %s...\n", maskURLCredentials(options.ImportURL))
+		return g.createImportedProject(ctx, payload)
+	case options.Skeleton != "":
+		// Check the skeleton before create the project
+		actions, err := skeletonActions(options.Skeleton)
+		if err != nil {
+			return gitlabProjectResponse{}, err
+		}
+
+		payload.InitializeWithReadME = false
+		project, err := g.createProject(ctx, payload)
+		if err != nil {
+			return project, err
+		}
+
+		err = g.createCommit(ctx, project.ID, gitlabCreateCommitRequest{
+			Branch:        options.DefaultBranch,
+			CommitMessage: "Initial commit",
+			Actions:       actions,
+		})
+		if err != nil {
+			return project, err
+		}
+
+		fmt.Printf("Skeleton committed with %d files\n", len(actions))
+		return project, nil
+	}
+
+	return g.createProject(ctx, payload)
+}
+
+// Create the project and wait for the import of its content
+func (g *gitlab) createImportedProject(ctx context.Context, payload gitlabCreateProjectRequest) (gitlabProjectResponse, error) {
+	project, err := g.createProject(ctx, payload)
+	if err != nil {
+		return project, err
+	}
+
+	waitCtx, cancel := context.WithTimeout(ctx, g.exportTimeout())
+	defer cancel()
+
+	err = waitImport(waitCtx, g.requestWithHeaders, project)
+	return project, g.exportError(ctx, project.PathWithNamespace, err)
+}
+
+// Create the project from a custom template.
+// The custom templates need a paid tier, otherwise the template is forked
+// and the fork relationship removed.
+func (g *gitlab) createProjectFromTemplate(ctx context.Context, options ProjectRequest, payload gitlabCreateProjectRequest) (gitlabProjectResponse, error) {
+	template, err := g.resolveProject(ctx, options.FromTemplate)
+	if err != nil {
+		return gitlabProjectResponse{}, err
+	}
+
+	fmt.Printf("Creating from the template %s...\n", template.PathWithNamespace)
+
+	templatePayload := payload
+	templatePayload.InitializeWithReadME = false
+	templatePayload.TemplateProjectID = template.ID
+	templatePayload.UseCustomTemplate = true
+
+	project, err := g.createImportedProject(ctx, templatePayload)
+	if err == nil || ctx.Err() != nil {
+		return project, err
+	}
+
+	// Fork only when the templates are not available
+	statusCode := helpers.StatusCode(err)
+	if project.ID != 0 || isAlreadyExists(err) || (statusCode != http.StatusBadRequest && statusCode != http.StatusForbidden && statusCode != http.StatusNotFound) {
+		return project, err
+	}
+
+	fmt.Printf("Custom templates not available (%s), forking the template...\n", err.Error())
+	return g.forkTemplate(ctx, template, payload)
+}
+
+func (g *gitlab) forkTemplate(ctx context.Context, template gitlabProjectResponse, payload gitlabCreateProjectRequest) (gitlabProjectResponse, error) {
+	var project gitlabProjectResponse
+
+	response, err := g.request(ctx, "POST", fmt.Sprintf("/projects/%d/fork", template.ID), map[string]interface{}{
+		"namespace_id": payload.NamespaceID,
+		"name":         payload.Name,
+		"path":         payload.Path,
+		"visibility":   payload.Visibility,
+	}, nil)
+	if err != nil {
+		return project, err
+	}
+
+	err = json.Unmarshal(response, &project)
+	if err != nil {
+		return project, err
+	}
+
+	waitCtx, cancel := context.WithTimeout(ctx, g.exportTimeout())
+	defer cancel()
+
+	err = waitImport(waitCtx, g.requestWithHeaders, project)
+	if err != nil {
+		return project, g.exportError(ctx, project.PathWithNamespace, err)
+	}
+
+	// The project must not be linked to the template
+	_, err = g.request(ctx, "DELETE", fmt.Sprintf("/projects/%d/fork", project.ID), nil, nil)
+	if err != nil {
+		return project, err
+	}
+
+	// The fork has the settings of the template, apply the default ones
+	_, err = g.request(ctx, "PUT", fmt.Sprintf("/projects/%d", project.ID), payload, nil)
+	return project, err
+}
+
+func (g *gitlab) createCommit(ctx context.Context, projectID int, commit gitlabCreateCommitRequest) error {
+	_, err := g.request(ctx, "POST", fmt.Sprintf("/projects/%d/repository/commits", projectID), commit, nil)
+	return err
+}
+
+// Read the files of the directory, sorted by path.
+// The .git directory is skipped.
+func readTemplateFiles(directory string) ([]gitlabTemplateFile, error) {
+	files := []gitlabTemplateFile{}
+
+	err := filepath.WalkDir(directory, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if entry.IsDir() {
+			if entry.Name() == ".git" {
+				return filepath.SkipDir
+			}
+
+			return nil
+		}
+
+		if !entry.Type().IsRegular() {
+			return nil
+		}
+
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+
+		relativePath, err := filepath.Rel(directory, path)
+		if err != nil {
+			return err
+		}
+
+		files = append(files, gitlabTemplateFile{
+			Path:       filepath.ToSlash(relativePath),
+			Content:    content,
+			Executable: info.Mode()&0111 != 0,
+		})
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(files) == 0 {
+		return nil, fmt.Errorf("the directory %s has no files", directory)
+	}
+
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].Path < files[j].Path
+	})
+
+	return files, nil
+}
+
+// The action of a commit writing the file
+func commitAction(action string, file gitlabTemplateFile) gitlabCommitAction {
+	return gitlabCommitAction{
+		Action:          action,
+		FilePath:        file.Path,
+		Content:         base64.StdEncoding.EncodeToString(file.Content),
+		Encoding:        "base64",
+		ExecuteFilemode: file.Executable,
+	}
+}
+
+// Read the files of the directory as the actions of a commit
+func skeletonActions(directory string) ([]gitlabCommitAction, error) {
+	files, err := readTemplateFiles(directory)
+	if err != nil {
+		return nil, err
+	}
+
+	actions := []gitlabCommitAction{}
+	for _, file := range files {
+		actions = append(actions, commitAction("create", file))
+	}
+
+	return actions, nil
+}
+
+// Make sure the branch exists, creating it from the default branch
+// of the repository. The imported repositories can use another name.
+func (g *gitlab) ensureBranch(ctx context.Context, projectID int, branch string) error {
+	_, err := viewBranch(ctx, g.requestWithHeaders, strconv.Itoa(projectID), branch)
+	if !helpers.IsNotFound(err) {
+		return err
+	}
+
+	var project gitlabProjectResponse
+	response, err := g.request(ctx, "GET", fmt.Sprintf("/projects/%d", projectID), nil, nil)
+	if err != nil {
+		return err
+	}
+
+	err = json.Unmarshal(response, &project)
+	if err != nil {
+		return err
+	}
+
+	if project.DefaultBranch == "" {
+		return fmt.Errorf("the repository of %s is empty", project.PathWithNamespace)
+	}
+
+	return g.createBranch(ctx, projectID, map[string]string{
+		"branch": branch,
+		"ref":    project.DefaultBranch,
+	})
+}
@@ -0,0 +1,205 @@
+package gitlab
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"opsi/helpers"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Check if the branch matches the name of a protected branch.
+// The names can contain wildcards. Eg: release/*
+func matchesProtectedBranch(name string, branch string) bool {
+	pattern := "^" + strings.ReplaceAll(regexp.QuoteMeta(name), `\*`, ".*") + "$"
+	matched, _ := regexp.MatchString(pattern, branch)
+	return matched
+}
+
+// Compare the files of the template with the ones of the branch
+// and return the actions needed to align them.
+func (g *gitlab) templateActions(ctx context.Context, projectID int, branch string, files []gitlabTemplateFile) ([]gitlabCommitAction, error) {
+	actions := []gitlabCommitAction{}
+	for _, file := range files {
+		endpoint := fmt.Sprintf("/projects/%d/repository/files/%s/raw", projectID, url.PathEscape(file.Path))
+		content, err := g.request(ctx, "GET", endpoint, nil, map[string]string{
+			"ref": branch,
+		})
+
+		if helpers.IsNotFound(err) {
+			actions = append(actions, commitAction("create", file))
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if !bytes.Equal(content, file.Content) {
+			actions = append(actions, commitAction("update", file))
+		}
+	}
+
+	return actions, nil
+}
+
+// Take the merge request opened from the branch, if any
+func (g *gitlab) findMergeRequest(ctx context.Context, projectID int, sourceBranch string, targetBranch string) (gitlabMergeRequest, bool, error) {
+	mergeRequests, err := collect[gitlabMergeRequest](ctx, g.requestWithHeaders, fmt.Sprintf("/projects/%d/merge_requests", projectID), map[string]string{
+		"state":         "opened",
+		"source_branch": sourceBranch,
+		"target_branch": targetBranch,
+	})
+	if err != nil || len(mergeRequests) == 0 {
+		return gitlabMergeRequest{}, false, err
+	}
+
+	return mergeRequests[0], true, nil
+}
+
+func (g *gitlab) syncProjectFiles(ctx context.Context, options SyncFilesRequest, project gitlabProjectResponse, files []gitlabTemplateFile) gitlabLifecycleResult {
+	result := gitlabLifecycleResult{
+		project: project.PathWithNamespace,
+	}
+
+	fail := func(err error) gitlabLifecycleResult {
+		result.status = gitlabLifecycleFailed
+		result.messages = append(result.messages, err.Error())
+		return result
+	}
+
+	if project.Archived || project.DefaultBranch == "" {
+		result.status = gitlabLifecycleSkipped
+		result.messages = []string{"archived or empty repository"}
+		return result
+	}
+
+	// Never push to the default and the protected branches
+	if options.Branch == project.DefaultBranch {
+		return fail(fmt.Errorf("the branch %s is the default branch", options.Branch))
+	}
+
+	protectedBranches, err := listProtectedBranches(ctx, g.requestWithHeaders, strconv.Itoa(project.ID))
+	if err != nil {
+		return fail(err)
+	}
+
+	for _, protectedBranch := range protectedBranches {
+		if matchesProtectedBranch(protectedBranch.Name, options.Branch) {
+			return fail(fmt.Errorf("the branch %s is protected", options.Branch))
+		}
+	}
+
+	actions, err := g.templateActions(ctx, project.ID, project.DefaultBranch, files)
+	if err != nil {
+		return fail(err)
+	}
+
+	if len(actions) == 0 {
+		result.status = gitlabSyncUpToDate
+		return result
+	}
+
+	paths := []string{}
+	for _, action := range actions {
+		paths = append(paths, action.FilePath)
+	}
+	result.messages = append(result.messages, "files: "+strings.Join(paths, ", "))
+
+	if options.DryRun {
+		result.status = gitlabLifecyclePlanned
+		return result
+	}
+
+	// The branch is always recreated from the default branch,
+	// so the merge request contains only the template changes.
+	err = g.createCommit(ctx, project.ID, gitlabCreateCommitRequest{
+		Branch:        options.Branch,
+		StartBranch:   project.DefaultBranch,
+		Force:         true,
+		CommitMessage: "Sync the standard files",
+		Actions:       actions,
+	})
+	if err != nil {
+		return fail(err)
+	}
+
+	mergeRequest, exists, err := g.findMergeRequest(ctx, project.ID, options.Branch, project.DefaultBranch)
+	if err != nil {
+		return fail(err)
+	}
+
+	if exists {
+		result.status = gitlabSyncMergeRequestUpdated
+		result.messages = append(result.messages, mergeRequest.WebURL)
+		return result
+	}
+
+	response, err := g.request(ctx, "POST", fmt.Sprintf("/projects/%d/merge_requests", project.ID), map[string]interface{}{
+		"source_branch":        options.Branch,
+		"target_branch":        project.DefaultBranch,
+		"title":                "Sync the standard files",
+		"description":          "Align the files with the standard template:\n\n- " + strings.Join(paths, "\n- "),
+		"remove_source_branch": true,
+	}, nil)
+	if err != nil {
+		return fail(err)
+	}
+
+	err = json.Unmarshal(response, &mergeRequest)
+	if err != nil {
+		return fail(err)
+	}
+
+	result.status = gitlabSyncMergeRequestOpened
+	result.messages = append(result.messages, mergeRequest.WebURL)
+	return result
+}
+
+func (g *gitlab) SyncFiles(ctx context.Context, options SyncFilesRequest) error {
+	if options.Template == "" {
+		return errors.New("missing template directory")
+	}
+
+	if options.Branch == "" {
+		options.Branch = gitlabSyncDefaultBranch
+	}
+
+	files, err := readTemplateFiles(options.Template)
+	if err != nil {
+		return err
+	}
+
+	projects, err := g.selectProjects(ctx, options.Project, options.Selector)
+	if err != nil {
+		return err
+	}
+
+	results := []gitlabLifecycleResult{}
+	for _, project := range projects {
+		// Don't touch other projects once interrupted
+		if ctx.Err() != nil {
+			break
+		}
+
+		results = append(results, g.syncProjectFiles(ctx, options, project, files))
+	}
+
+	// Print the report
+	failed := printLifecycleResults(results)
+
+	err = helpers.Interrupted(ctx, len(results), len(projects), "projects")
+	if err != nil {
+		return err
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d projects failed", failed)
+	}
+
+	return nil
+}
@@ -0,0 +1,28 @@
+package gitlab
+
+import "testing"
+
+func TestMatchesProtectedBranch(t *testing.T) {
+	tests := []struct {
+		name    string
+		branch  string
+		matches bool
+	}{
+		{"main", "main", true},
+		{"main", "main-old", false},
+		{"release/*", "release/1.0", true},
+		{"release/*", "release/", true},
+		{"release/*", "hotfix/release/1.0", false},
+		{"*-stable", "1-0-stable", true},
+		{"*", "opsi/sync-files", true},
+		{"v1.0", "v1x0", false},
+		{"feature/[a]", "feature/[a]", true},
+	}
+
+	for _, test := range tests {
+		matches := matchesProtectedBranch(test.name, test.branch)
+		if matches != test.matches {
+			t.Errorf("matchesProtectedBranch(%q, %q) = %v; want %v", test.name, test.branch, matches, test.matches)
+		}
+	}
+}
@@ -0,0 +1,436 @@
+package gitlab
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"opsi/helpers"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// The events supported by the webhooks of the projects and groups
+var gitlabWebhookEvents = []string{
+	"push",
+	"tag_push",
+	"issues",
+	"confidential_issues",
+	"note",
+	"confidential_note",
+	"merge_requests",
+	"job",
+	"pipeline",
+	"wiki_page",
+	"deployment",
+	"releases",
+}
+
+func (h *gitlabHook) UnmarshalJSON(data []byte) error {
+	type plainHook gitlabHook
+	err := json.Unmarshal(data, (*plainHook)(h))
+	if err != nil {
+		return err
+	}
+
+	var fields map[string]interface{}
+	err = json.Unmarshal(data, &fields)
+	if err != nil {
+		return err
+	}
+
+	_, h.HasDescription = fields["description"]
+	h.Events = map[string]bool{}
+	for key, value := range fields {
+		enabled, ok := value.(bool)
+		if ok && strings.HasSuffix(key, "_events") {
+			h.Events[strings.TrimSuffix(key, "_events")] = enabled
+		}
+	}
+
+	return nil
+}
+
+// The enabled events, sorted. Eg: pipeline, push
+func (h gitlabHook) enabledEvents() []string {
+	events := []string{}
+	for event, enabled := range h.Events {
+		if enabled {
+			events = append(events, event)
+		}
+	}
+	sort.Strings(events)
+
+	return events
+}
+
+func webhookLevel(webhook GitlabWebhookOptions) string {
+	if webhook.Level == "" {
+		return gitlabWebhookLevelProject
+	}
+
+	return webhook.Level
+}
+
+// The webhooks of the project
+func (g *gitlab) projectWebhooks(project gitlabProjectResponse) []GitlabWebhookOptions {
+	webhooks := []GitlabWebhookOptions{}
+	for _, webhook := range g.webhooks {
+		if webhookLevel(webhook) != gitlabWebhookLevelProject {
+			continue
+		}
+
+		matches := len(webhook.Groups) == 0
+		for _, glob := range webhook.Groups {
+			matches = matches || matchesNamespaceGlob(glob, project)
+		}
+
+		if matches {
+			webhooks = append(webhooks, webhook)
+		}
+	}
+
+	return webhooks
+}
+
+// The webhooks of the group.
+// Without globs only the top level groups are taken.
+func (g *gitlab) groupWebhooks(group gitlabSubgroupResponse) []GitlabWebhookOptions {
+	webhooks := []GitlabWebhookOptions{}
+	for _, webhook := range g.webhooks {
+		if webhookLevel(webhook) != gitlabWebhookLevelGroup {
+			continue
+		}
+
+		matches := len(webhook.Groups) == 0 && group.ParentID == 0
+		for _, glob := range webhook.Groups {
+			matched, err := path.Match(strings.Trim(glob, "/"), group.FullPath)
+			matches = matches || (err == nil && matched)
+		}
+
+		if matches {
+			webhooks = append(webhooks, webhook)
+		}
+	}
+
+	return webhooks
+}
+
+// Read the secret through the resolver.
+// The secrets are read once per run.
+func (g *gitlab) secret(ctx context.Context, reference string) (string, error) {
+	g.secretsMutex.Lock()
+	defer g.secretsMutex.Unlock()
+
+	if value, ok := g.secretsCache[reference]; ok {
+		return value, nil
+	}
+
+	if g.secrets == nil {
+		return "", errors.New("no secret resolver available")
+	}
+
+	value, err := g.secrets(ctx, reference)
+	if err != nil {
+		return "", err
+	}
+
+	g.secretsCache[reference] = value
+	return value, nil
+}
+
+// The description of the hooks of opsi: the marker and the name of the webhook.
+// Eg: opsi:ci-notifications
+func webhookDescription(name string) string {
+	return gitlabWebhookMarker + name
+}
+
+// The name of the webhook of opsi, false for the other hooks
+func webhookName(description string) (string, bool) {
+	if !strings.HasPrefix(description, gitlabWebhookMarker) {
+		return "", false
+	}
+
+	return strings.TrimPrefix(description, gitlabWebhookMarker), true
+}
+
+// The payload of the webhook. All the events are provided,
+// so the events removed from the configuration are disabled.
+func (g *gitlab) webhookPayload(ctx context.Context, webhook GitlabWebhookOptions) (map[string]interface{}, error) {
+	payload := map[string]interface{}{
+		"url":                     webhook.URL,
+		"name":                    webhook.Name,
+		"description":             webhookDescription(webhook.Name),
+		"enable_ssl_verification": webhook.SSLVerification == nil || *webhook.SSLVerification,
+	}
+
+	enabled := map[string]bool{}
+	for _, event := range webhook.Events {
+		enabled[event] = true
+	}
+
+	for _, event := range gitlabWebhookEvents {
+		payload[event+"_events"] = enabled[event]
+		delete(enabled, event)
+	}
+
+	for event := range enabled {
+		return nil, fmt.Errorf("unknown event %s of the webhook %s", event, webhook.Name)
+	}
+
+	if webhook.Secret != "" {
+		token, err := g.secret(ctx, webhook.Secret)
+		if err != nil {
+			return nil, err
+		}
+
+		payload["token"] = token
+	}
+
+	return payload, nil
+}
+
+// Check if the hook has the settings of the webhook.
+// The secret can't be read back, so it is not compared.
+// Before GitLab 17.1 the hooks have no description to compare.
+func hookMatches(hook gitlabHook, payload map[string]interface{}) bool {
+	if hook.URL != payload["url"] || hook.EnableSSLVerification != payload["enable_ssl_verification"] {
+		return false
+	}
+
+	if hook.HasDescription && hook.Description != payload["description"] {
+		return false
+	}
+
+	for _, event := range gitlabWebhookEvents {
+		if hook.Events[event] != payload[event+"_events"] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Align the hooks of the project or group with the webhooks provided.
+// The hooks are matched by the marker of opsi or by URL. The hooks with
+// the marker not configured anymore are removed, the others are untouched.
+// Before GitLab 17.1 the hooks have no description, so no marker: they are
+// matched only by URL and the ones not configured anymore are kept.
+// Eg: /projects/1234, /groups/56
+func (g *gitlab) reconcileHooks(ctx context.Context, entityEndpoint string, webhooks []GitlabWebhookOptions) ([]string, error) {
+	changes := []string{}
+	endpoint := entityEndpoint + "/hooks"
+
+	hooks, err := collect[gitlabHook](ctx, g.requestWithHeaders, endpoint, nil)
+	if err != nil {
+		return changes, err
+	}
+
+	used := map[int]bool{}
+	for _, webhook := range webhooks {
+		payload, err := g.webhookPayload(ctx, webhook)
+		if err != nil {
+			return changes, err
+		}
+
+		var current *gitlabHook
+		for index, hook := range hooks {
+			name, _ := webhookName(hook.Description)
+			if !used[hook.ID] && (name == webhook.Name || hook.URL == webhook.URL) {
+				current = &hooks[index]
+				break
+			}
+		}
+
+		if current == nil {
+			_, err = g.request(ctx, "POST", endpoint, payload, nil)
+			if err != nil {
+				return changes, fmt.Errorf("webhook %s: %s", webhook.Name, err.Error())
+			}
+
+			changes = append(changes, "webhook "+webhook.Name+" created")
+			continue
+		}
+
+		// The secret is sent again on each run, a rotated secret can't be detected
+		used[current.ID] = true
+		matches := hookMatches(*current, payload)
+		if matches && webhook.Secret == "" {
+			continue
+		}
+
+		_, err = g.request(ctx, "PUT", fmt.Sprintf("%s/%d", endpoint, current.ID), payload, nil)
+		if err != nil {
+			return changes, fmt.Errorf("webhook %s: %s", webhook.Name, err.Error())
+		}
+
+		if !matches {
+			changes = append(changes, "webhook "+webhook.Name+" updated")
+		}
+	}
+
+	// Remove the hooks of opsi not configured anymore
+	for _, hook := range hooks {
+		name, ok := webhookName(hook.Description)
+		if used[hook.ID] || !ok {
+			continue
+		}
+
+		_, err = g.request(ctx, "DELETE", fmt.Sprintf("%s/%d", endpoint, hook.ID), nil, nil)
+		if err != nil && !helpers.IsNotFound(err) {
+			return changes, err
+		}
+
+		changes = append(changes, "webhook "+name+" removed")
+	}
+
+	return changes, nil
+}
+
+func (g *gitlab) reconcileProjectHooks(ctx context.Context, project gitlabProjectResponse) ([]string, error) {
+	return g.reconcileHooks(ctx, "/projects/"+strconv.Itoa(project.ID), g.projectWebhooks(project))
+}
+
+// Align the hooks of all the groups.
+// The groups without group webhooks are skipped: the group hooks need
+// a paid tier, so the free instances are never called.
+func (g *gitlab) reconcileGroupsHooks(ctx context.Context, channel *chan string) error {
+	hasGroupWebhooks := false
+	for _, webhook := range g.webhooks {
+		hasGroupWebhooks = hasGroupWebhooks || webhookLevel(webhook) == gitlabWebhookLevelGroup
+	}
+
+	if !hasGroupWebhooks {
+		return nil
+	}
+
+	groups, err := collect[gitlabSubgroupResponse](ctx, g.cachedRequestWithHeaders, "/groups", nil)
+	if err != nil {
+		return err
+	}
+
+	for index, group := range groups {
+		if ctx.Err() != nil {
+			return helpers.Interrupted(ctx, index, len(groups), "groups")
+		}
+
+		changes, err := g.reconcileHooks(ctx, "/groups/"+strconv.Itoa(group.ID), g.groupWebhooks(group))
+		for _, change := range changes {
+			*channel <- fmt.Sprintf("Group %s: %s", group.FullPath, change)
+		}
+
+		if err != nil {
+			*channel <- fmt.Sprintf("Error on setup webhooks for group %s: %s", group.FullPath, err.Error())
+		}
+	}
+
+	return nil
+}
+
+// Check if the hook is one of the webhooks configured
+func (g *gitlab) isStandardHook(hook gitlabHook) bool {
+	for _, webhook := range g.webhooks {
+		name, _ := webhookName(hook.Description)
+		if hook.URL == webhook.URL || name == webhook.Name {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (g *gitlab) ListHooks(ctx context.Context, options ListHooksRequest) error {
+	err := helpers.ValidateOutput(options.Output)
+	if err != nil {
+		return err
+	}
+
+	if options.Output == helpers.OutputTree {
+		return errors.New("the tree output is not available for the hooks")
+	}
+
+	// Take the projects, all of them without a project or a selector
+	var projects []gitlabProjectResponse
+	if options.Project != "" || options.Selector != "" {
+		projects, err = g.selectProjects(ctx, options.Project, options.Selector)
+	} else {
+		projects, err = g.allProjects(ctx)
+	}
+	if err != nil {
+		return err
+	}
+
+	rows := []gitlabHookRow{}
+	addRows := func(kind string, fullPath string, hooks []gitlabHook) {
+		for _, hook := range hooks {
+			standard := g.isStandardHook(hook)
+			if standard && !options.All {
+				continue
+			}
+
+			rows = append(rows, gitlabHookRow{
+				Kind:     kind,
+				FullPath: fullPath,
+				URL:      maskURLCredentials(hook.URL),
+				Events:   strings.Join(hook.enabledEvents(), ","),
+				SSL:      hook.EnableSSLVerification,
+				Standard: standard,
+			})
+		}
+	}
+
+	for index, project := range projects {
+		if ctx.Err() != nil {
+			return helpers.Interrupted(ctx, index, len(projects), "projects")
+		}
+
+		hooks, err := collect[gitlabHook](ctx, g.requestWithHeaders, fmt.Sprintf("/projects/%d/hooks", project.ID), nil)
+		if err != nil {
+			return err
+		}
+
+		addRows(gitlabWebhookLevelProject, project.PathWithNamespace, hooks)
+	}
+
+	// The hooks of the groups are listed only for the whole instance.
+	// They need a paid tier, so the forbidden groups are skipped.
+	if options.Project == "" && options.Selector == "" {
+		groups, err := collect[gitlabSubgroupResponse](ctx, g.cachedRequestWithHeaders, "/groups", nil)
+		if err != nil {
+			return err
+		}
+
+		for index, group := range groups {
+			if ctx.Err() != nil {
+				return helpers.Interrupted(ctx, index, len(groups), "groups")
+			}
+
+			hooks, err := collect[gitlabHook](ctx, g.requestWithHeaders, fmt.Sprintf("/groups/%d/hooks", group.ID), nil)
+			if helpers.StatusCode(err) == 403 || helpers.IsNotFound(err) {
+				continue
+			}
+			if err != nil {
+				return err
+			}
+
+			addRows(gitlabWebhookLevelGroup, group.FullPath, hooks)
+		}
+	}
+
+	headers := []string{"KIND", "PATH", "URL", "EVENTS", "SSL", "STANDARD"}
+	table := [][]string{}
+	for _, row := range rows {
+		table = append(table, []string{
+			row.Kind,
+			row.FullPath,
+			row.URL,
+			row.Events,
+			strconv.FormatBool(row.SSL),
+			strconv.FormatBool(row.Standard),
+		})
+	}
+
+	return helpers.PrintOutput(options.Output, headers, table, rows)
+}
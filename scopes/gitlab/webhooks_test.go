@@ -0,0 +1,205 @@
+package gitlab
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestHookUnmarshal(t *testing.T) {
+	var hook gitlabHook
+	err := json.Unmarshal([]byte(`{
+		"id": 3,
+		"url": "https://hooks.example.com/gitlab",
+		"description": "opsi:ci",
+		"enable_ssl_verification": true,
+		"push_events": true,
+		"pipeline_events": true,
+		"tag_push_events": false,
+		"push_events_branch_filter": "main"
+	}`), &hook)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if hook.ID != 3 || hook.URL != "https://hooks.example.com/gitlab" || !hook.EnableSSLVerification || !hook.HasDescription {
+		t.Errorf("unexpected hook %+v", hook)
+	}
+
+	events := hook.enabledEvents()
+	if !reflect.DeepEqual(events, []string{"pipeline", "push"}) {
+		t.Errorf("enabledEvents = %v; want [pipeline push]", events)
+	}
+}
+
+func TestHookUnmarshalWithoutDescription(t *testing.T) {
+	var hook gitlabHook
+	err := json.Unmarshal([]byte(`{"id": 3, "url": "https://hooks.example.com/gitlab", "push_events": true}`), &hook)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if hook.HasDescription {
+		t.Errorf("the hook %+v has a description", hook)
+	}
+}
+
+func TestWebhookName(t *testing.T) {
+	tests := []struct {
+		description string
+		name        string
+		ok          bool
+	}{
+		{"opsi:ci", "ci", true},
+		{webhookDescription("ci-notifications"), "ci-notifications", true},
+		{"added by hand", "", false},
+		{"", "", false},
+	}
+
+	for _, test := range tests {
+		name, ok := webhookName(test.description)
+		if name != test.name || ok != test.ok {
+			t.Errorf("webhookName(%q) = %q, %v; want %q, %v", test.description, name, ok, test.name, test.ok)
+		}
+	}
+}
+
+func TestWebhookPayload(t *testing.T) {
+	secrets := map[string]string{"op://ops/webhook/token": "first"}
+	g := &gitlab{
+		secretsCache: map[string]string{},
+		secrets: func(ctx context.Context, reference string) (string, error) {
+			return secrets[reference], nil
+		},
+	}
+
+	webhook := GitlabWebhookOptions{
+		Name:   "ci",
+		URL:    "https://hooks.example.com/gitlab",
+		Events: []string{"push", "pipeline"},
+		Secret: "op://ops/webhook/token",
+	}
+
+	payload, err := g.webhookPayload(context.Background(), webhook)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if payload["token"] != "first" || payload["enable_ssl_verification"] != true {
+		t.Errorf("unexpected payload %v", payload)
+	}
+
+	if payload["push_events"] != true || payload["pipeline_events"] != true || payload["job_events"] != false {
+		t.Errorf("unexpected events in payload %v", payload)
+	}
+
+	// Nothing derived from the secret is stored in the description
+	if payload["description"] != "opsi:ci" {
+		t.Errorf("unexpected description %v", payload["description"])
+	}
+
+	webhook.Events = []string{"push", "unknown"}
+	_, err = g.webhookPayload(context.Background(), webhook)
+	if err == nil {
+		t.Error("webhookPayload accepts an unknown event")
+	}
+}
+
+func TestHookMatches(t *testing.T) {
+	events := map[string]bool{}
+	for _, event := range gitlabWebhookEvents {
+		events[event] = event == "push"
+	}
+
+	payload := map[string]interface{}{
+		"url":                     "https://hooks.example.com/gitlab",
+		"description":             webhookDescription("ci"),
+		"enable_ssl_verification": true,
+	}
+	for event, enabled := range events {
+		payload[event+"_events"] = enabled
+	}
+
+	hook := gitlabHook{
+		URL:                   "https://hooks.example.com/gitlab",
+		Description:           webhookDescription("ci"),
+		HasDescription:        true,
+		EnableSSLVerification: true,
+		Events:                events,
+	}
+
+	tests := []struct {
+		name    string
+		change  func(hook *gitlabHook)
+		matches bool
+	}{
+		{"same", func(hook *gitlabHook) {}, true},
+		{"url", func(hook *gitlabHook) { hook.URL = "https://other.example.com/gitlab" }, false},
+		{"ssl", func(hook *gitlabHook) { hook.EnableSSLVerification = false }, false},
+		{"description", func(hook *gitlabHook) { hook.Description = "opsi:ci 3f2a9c0d1e7b5a46" }, false},
+		{"no description before 17.1", func(hook *gitlabHook) { hook.Description, hook.HasDescription = "", false }, true},
+		{"event added", func(hook *gitlabHook) { hook.Events = map[string]bool{"push": true, "job": true} }, false},
+		{"event removed", func(hook *gitlabHook) { hook.Events = map[string]bool{} }, false},
+	}
+
+	for _, test := range tests {
+		changed := hook
+		test.change(&changed)
+
+		matches := hookMatches(changed, payload)
+		if matches != test.matches {
+			t.Errorf("%s: hookMatches = %v; want %v", test.name, matches, test.matches)
+		}
+	}
+}
+
+func TestWebhooksSelection(t *testing.T) {
+	g := &gitlab{
+		webhooks: []GitlabWebhookOptions{
+			{Name: "all"},
+			{Name: "clients", Level: gitlabWebhookLevelProject, Groups: []string{"clients/*"}},
+			{Name: "top", Level: gitlabWebhookLevelGroup},
+			{Name: "acme", Level: gitlabWebhookLevelGroup, Groups: []string{"clients/acme"}},
+		},
+	}
+
+	names := func(webhooks []GitlabWebhookOptions) []string {
+		result := []string{}
+		for _, webhook := range webhooks {
+			result = append(result, webhook.Name)
+		}
+		return result
+	}
+
+	projectTests := []struct {
+		path  string
+		names []string
+	}{
+		{"clients/acme/website", []string{"all", "clients"}},
+		{"internal/tools", []string{"all"}},
+	}
+
+	for _, test := range projectTests {
+		selected := names(g.projectWebhooks(gitlabProjectResponse{PathWithNamespace: test.path}))
+		if !reflect.DeepEqual(selected, test.names) {
+			t.Errorf("projectWebhooks(%q) = %v; want %v", test.path, selected, test.names)
+		}
+	}
+
+	groupTests := []struct {
+		group gitlabSubgroupResponse
+		names []string
+	}{
+		{gitlabSubgroupResponse{FullPath: "clients"}, []string{"top"}},
+		{gitlabSubgroupResponse{FullPath: "clients/acme", ParentID: 1}, []string{"acme"}},
+		{gitlabSubgroupResponse{FullPath: "clients/other", ParentID: 1}, []string{}},
+	}
+
+	for _, test := range groupTests {
+		selected := names(g.groupWebhooks(test.group))
+		if !reflect.DeepEqual(selected, test.names) {
+			t.Errorf("groupWebhooks(%q) = %v; want %v", test.group.FullPath, selected, test.names)
+		}
+	}
+}
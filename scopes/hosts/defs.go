@@ -1,5 +1,7 @@
 package hosts
 
+import "context"
+
 type hostHSSH struct {
 	Name     string
 	Hostname string
@@ -11,5 +13,6 @@ type hostHSSH struct {
 type host struct{}
 
 type Host interface {
-	CheckReboot() error
+	CheckReboot(context.Context, []string) error
+	CompleteHosts(context.Context, string) ([]string, error)
 }
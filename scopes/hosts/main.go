@@ -3,7 +3,9 @@ package hosts
 import (
 	"bufio"
 	"bytes"
+	"context"
 	"fmt"
+	"opsi/helpers"
 	"os/exec"
 	"regexp"
 	"strconv"
@@ -22,26 +24,24 @@ func (o *host) createPortString(host hostHSSH) string {
 	return fmt.Sprintf("%d", host.Port)
 }
 
-func (o *host) listHosts() ([]hostHSSH, []string, error) {
-
+// Take the names of the hosts known by hssh
+func (o *host) listHostNames(ctx context.Context) ([]string, error) {
 	var stdout, stderr bytes.Buffer
 
-	cmd := exec.Command("hssh", "l")
+	cmd := exec.CommandContext(ctx, "hssh", "l")
 	cmd.Stderr = &stderr
 	cmd.Stdout = &stdout
 
 	err := cmd.Run()
 	if err != nil {
-		return nil, nil, err
+		return nil, err
 	}
 
 	reader := bytes.NewReader(stdout.Bytes())
 	scanner := bufio.NewScanner(reader)
 	scanner.Split(bufio.ScanLines)
 
-	list := []hostHSSH{}
-	ignoredHosts := []string{}
-
+	names := []string{}
 	for scanner.Scan() {
 		text := scanner.Text()
 		partials := strings.Split(text, "->")
@@ -50,8 +50,37 @@ func (o *host) listHosts() ([]hostHSSH, []string, error) {
 		}
 
 		hostname := strings.Trim(partials[0], " ")
+		if hostname != "" {
+			names = append(names, hostname)
+		}
+	}
+
+	return names, nil
+}
+
+// Take the details of the hosts.
+// If some names are provided, only these hosts are taken.
+func (o *host) listHosts(ctx context.Context, filter []string) ([]hostHSSH, []string, error) {
+	names, err := o.listHostNames(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	list := []hostHSSH{}
+	ignoredHosts := []string{}
 
-		host, err := o.findHost(hostname)
+	requested := map[string]bool{}
+	for _, name := range filter {
+		requested[name] = true
+	}
+
+	for _, hostname := range names {
+		if len(requested) > 0 && !requested[hostname] {
+			continue
+		}
+		delete(requested, hostname)
+
+		host, err := o.findHost(ctx, hostname)
 		if err != nil {
 			ignoredHosts = append(ignoredHosts, hostname)
 			continue
@@ -60,14 +89,19 @@ func (o *host) listHosts() ([]hostHSSH, []string, error) {
 		list = append(list, host)
 	}
 
+	// The hosts requested but unknown by hssh
+	for hostname := range requested {
+		ignoredHosts = append(ignoredHosts, hostname)
+	}
+
 	return list, ignoredHosts, nil
 }
 
-func (o *host) findHost(hostname string) (hostHSSH, error) {
+func (o *host) findHost(ctx context.Context, hostname string) (hostHSSH, error) {
 	host := hostHSSH{}
 	var stdout, stderr bytes.Buffer
 
-	cmd := exec.Command("hssh", "f", hostname)
+	cmd := exec.CommandContext(ctx, "hssh", "f", hostname)
 	cmd.Stderr = &stderr
 	cmd.Stdout = &stdout
 
@@ -109,19 +143,25 @@ func (o *host) findHost(hostname string) (hostHSSH, error) {
 	return host, nil
 }
 
-func (o *host) CheckReboot() error {
+func (o *host) CheckReboot(ctx context.Context, names []string) error {
 	listErrors := []string{}
 	listRebootable := []string{}
 	listUnrebootable := []string{}
 
-	hosts, listIgnored, err := o.listHosts()
+	hosts, listIgnored, err := o.listHosts(ctx, names)
 	if err != nil {
 		return err
 	}
 
 	statusRgx := regexp.MustCompile(`exit status ([0-9]+)`)
 
+	checked := 0
 	for _, host := range hosts {
+		// Don't connect to other hosts once interrupted
+		if ctx.Err() != nil {
+			break
+		}
+
 		var stdout, stderr bytes.Buffer
 		command := []string{
 			o.createConnectionString(host),
@@ -136,11 +176,15 @@ func (o *host) CheckReboot() error {
 			`ls -la /var/run/reboot-required`,
 		}
 
-		cmd := exec.Command("ssh", command...)
+		// The connection already open gets the grace period to complete
+		sshCtx, cancel := helpers.WithGracePeriod(ctx)
+		cmd := exec.CommandContext(sshCtx, "ssh", command...)
 		cmd.Stdout = &stdout
 		cmd.Stderr = &stderr
 
 		err = cmd.Run()
+		cancel()
+		checked++
 
 		if err != nil {
 			statusCode := statusRgx.ReplaceAllString(err.Error(), "$1")
@@ -176,8 +220,27 @@ func (o *host) CheckReboot() error {
 		fmt.Println("-", host)
 	}
 
-	return nil
+	return helpers.Interrupted(ctx, checked, len(hosts), "hosts")
+}
+
+// Complete the names of the hosts.
+// The names are cached because hssh is slow on big inventories.
+func (o *host) CompleteHosts(ctx context.Context, toComplete string) ([]string, error) {
+	names, err := helpers.CachedList("hssh-hosts", func() ([]string, error) {
+		return o.listHostNames(ctx)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	completions := []string{}
+	for _, name := range names {
+		if strings.HasPrefix(name, toComplete) {
+			completions = append(completions, name)
+		}
+	}
 
+	return completions, nil
 }
 
 func NewHosts() Host {
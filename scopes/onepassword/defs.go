@@ -1,13 +1,16 @@
 package onepassword
 
+import "context"
+
 type onePassword struct {
 	address string
 	account OnePasswordAccount
 }
 
 type OnePassword interface {
-	Deprovisioning(string) error
-	Create(string) error
+	Deprovisioning(context.Context, string) error
+	Create(context.Context, string) error
+	Read(context.Context, string) (string, error)
 }
 
 type OnePasswordUser struct {
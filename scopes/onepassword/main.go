@@ -2,6 +2,7 @@ package onepassword
 
 import (
 	"bufio"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -12,17 +13,17 @@ import (
 )
 
 // Execute any OP command.
-func (o *onePassword) executeCommand(args ...string) ([]byte, error) {
-	return helpers.Exec("op", args...)
+func (o *onePassword) executeCommand(ctx context.Context, args ...string) ([]byte, error) {
+	return helpers.Exec(ctx, "op", args...)
 }
 
-func (o *onePassword) grantPermissions(vaultName string, userGroup string, permissions []string) error {
-	_, err := o.executeCommand("vault", "group", "grant", "--vault", vaultName, "--group", userGroup, "--permissions", strings.Join(permissions, ","))
+func (o *onePassword) grantPermissions(ctx context.Context, vaultName string, userGroup string, permissions []string) error {
+	_, err := o.executeCommand(ctx, "vault", "group", "grant", "--vault", vaultName, "--group", userGroup, "--permissions", strings.Join(permissions, ","))
 	return err
 }
 
-func (o *onePassword) listAccounts() ([]OnePasswordAccount, error) {
-	output, err := o.executeCommand("account", "list", "--format", "json")
+func (o *onePassword) listAccounts(ctx context.Context) ([]OnePasswordAccount, error) {
+	output, err := o.executeCommand(ctx, "account", "list", "--format", "json")
 	if err != nil {
 		return nil, err
 	}
@@ -43,8 +44,8 @@ func (o *onePassword) listAccounts() ([]OnePasswordAccount, error) {
 	return accountFilteredByAddress, nil
 }
 
-func (o *onePassword) listVaults() ([]OnePasswordVault, error) {
-	output, err := o.executeCommand("vault", "list", "--format", "json")
+func (o *onePassword) listVaults(ctx context.Context) ([]OnePasswordVault, error) {
+	output, err := o.executeCommand(ctx, "vault", "list", "--format", "json")
 	if err != nil {
 		return nil, err
 	}
@@ -54,8 +55,8 @@ func (o *onePassword) listVaults() ([]OnePasswordVault, error) {
 	return listOfVaults, err
 }
 
-func (o *onePassword) listGroups() ([]OnePasswordGroup, error) {
-	output, err := o.executeCommand("group", "list", "--format", "json")
+func (o *onePassword) listGroups(ctx context.Context) ([]OnePasswordGroup, error) {
+	output, err := o.executeCommand(ctx, "group", "list", "--format", "json")
 	if err != nil {
 		return nil, err
 	}
@@ -65,28 +66,28 @@ func (o *onePassword) listGroups() ([]OnePasswordGroup, error) {
 	return listOfGroups, err
 }
 
-func (o *onePassword) createGroup(groupName string) error {
-	_, err := o.executeCommand("group", "create", groupName)
+func (o *onePassword) createGroup(ctx context.Context, groupName string) error {
+	_, err := o.executeCommand(ctx, "group", "create", groupName)
 	return err
 }
 
-func (o *onePassword) createVault(vaultName string) error {
-	_, err := o.executeCommand("vault", "create", vaultName)
+func (o *onePassword) createVault(ctx context.Context, vaultName string) error {
+	_, err := o.executeCommand(ctx, "vault", "create", vaultName)
 	return err
 }
 
-func (o *onePassword) revokeUserFromGroup(groupName string) error {
-	_, err := o.executeCommand("group", "user", "revoke", "--user", o.account.UserUUID, "--group", groupName)
+func (o *onePassword) revokeUserFromGroup(ctx context.Context, groupName string) error {
+	_, err := o.executeCommand(ctx, "group", "user", "revoke", "--user", o.account.UserUUID, "--group", groupName)
 	return err
 }
 
-func (o *onePassword) revokeUserFromVault(vaultName string) error {
-	_, err := o.executeCommand("vault", "user", "revoke", "--user", o.account.UserUUID, "--vault", vaultName)
+func (o *onePassword) revokeUserFromVault(ctx context.Context, vaultName string) error {
+	_, err := o.executeCommand(ctx, "vault", "user", "revoke", "--user", o.account.UserUUID, "--vault", vaultName)
 	return err
 }
 
-func (o *onePassword) addVault(vaultName string) error {
-	listOfVaults, err := o.listVaults()
+func (o *onePassword) addVault(ctx context.Context, vaultName string) error {
+	listOfVaults, err := o.listVaults(ctx)
 	if err != nil {
 		return err
 	}
@@ -102,11 +103,11 @@ func (o *onePassword) addVault(vaultName string) error {
 	}
 
 	// Create vault and return output response
-	return o.createVault(vaultName)
+	return o.createVault(ctx, vaultName)
 }
 
-func (o *onePassword) addGroup(groupName string) error {
-	listOfGroups, err := o.listGroups()
+func (o *onePassword) addGroup(ctx context.Context, groupName string) error {
+	listOfGroups, err := o.listGroups(ctx)
 	if err != nil {
 		return err
 	}
@@ -122,48 +123,48 @@ func (o *onePassword) addGroup(groupName string) error {
 	}
 
 	// Create group
-	return o.createGroup(groupName)
+	return o.createGroup(ctx, groupName)
 }
 
-func (o *onePassword) createContainer(projectName string, permissions []string) error {
-	err := o.addGroup(projectName)
+func (o *onePassword) createContainer(ctx context.Context, projectName string, permissions []string) error {
+	err := o.addGroup(ctx, projectName)
 	if err != nil {
 		return err
 	}
 
-	err = o.addVault(projectName)
+	err = o.addVault(ctx, projectName)
 	if err != nil {
 		return err
 	}
 
-	err = o.grantPermissions(projectName, "Owners", privilegedPermissions)
+	err = o.grantPermissions(ctx, projectName, "Owners", privilegedPermissions)
 	if err != nil {
 		return err
 	}
 
-	err = o.grantPermissions(projectName, "Administrators", privilegedPermissions)
+	err = o.grantPermissions(ctx, projectName, "Administrators", privilegedPermissions)
 	if err != nil {
 		return err
 	}
 
-	err = o.grantPermissions(projectName, projectName, permissions)
+	err = o.grantPermissions(ctx, projectName, projectName, permissions)
 	if err != nil {
 		return err
 	}
 
-	err = o.revokeUserFromGroup(projectName)
+	err = o.revokeUserFromGroup(ctx, projectName)
 	if err != nil {
 		return err
 	}
 
-	return o.revokeUserFromVault(projectName)
+	return o.revokeUserFromVault(ctx, projectName)
 }
 
-func (o *onePassword) Create(projectName string) error {
+func (o *onePassword) Create(ctx context.Context, projectName string) error {
 	priName := projectName + " - PRI"
 	pubName := projectName + " - PUB"
 
-	listOfAccounts, err := o.listAccounts()
+	listOfAccounts, err := o.listAccounts(ctx)
 	if err != nil {
 		return err
 	}
@@ -197,23 +198,23 @@ func (o *onePassword) Create(projectName string) error {
 	}
 
 	// Create primary container
-	err = o.createContainer(priName, unprivilegedPriPermissions)
+	err = o.createContainer(ctx, priName, unprivilegedPriPermissions)
 	if err != nil {
 		return err
 	}
 
 	// Create public container
-	err = o.createContainer(pubName, unprivilegedPubPermissions)
+	err = o.createContainer(ctx, pubName, unprivilegedPubPermissions)
 	if err != nil {
 		return err
 	}
 
 	// Grant permissions
-	return o.grantPermissions(pubName, priName, unprivilegedPriPermissions)
+	return o.grantPermissions(ctx, pubName, priName, unprivilegedPriPermissions)
 }
 
-func (o *onePassword) Deprovisioning(userEmail string) error {
-	output, err := o.executeCommand("user", "list", "--format", "json")
+func (o *onePassword) Deprovisioning(ctx context.Context, userEmail string) error {
+	output, err := o.executeCommand(ctx, "user", "list", "--format", "json")
 	if err != nil {
 		return err
 	}
@@ -261,6 +262,22 @@ func (o *onePassword) Deprovisioning(userEmail string) error {
 	return nil
 }
 
+// Read a secret by its reference.
+// Eg: op://Ops/Sentry webhook/password
+func (o *onePassword) Read(ctx context.Context, reference string) (string, error) {
+	args := []string{"read", "--no-newline", reference}
+	if o.address != "" {
+		args = append(args, "--account", o.address)
+	}
+
+	output, err := o.executeCommand(ctx, args...)
+	if err != nil {
+		return "", fmt.Errorf("cannot read the secret %s: %s", reference, strings.TrimSpace(err.Error()))
+	}
+
+	return string(output), nil
+}
+
 func NewOnePassword(address string) OnePassword {
 	return &onePassword{
 		address: address,